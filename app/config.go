@@ -1,7 +1,9 @@
 package app
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/TIBCOSoftware/flogo-lib/app/resource"
@@ -23,6 +25,68 @@ type Config struct {
 
 	//for backwards compatibility
 	Actions []*action.Config `json:"actions"`
+
+	// PropertyDefs are the property declarations, when the properties are declared
+	// Properties contains their default values
+	PropertyDefs []*PropertyDef `json:"-"`
+}
+
+type configAlias Config
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON, properties can either
+// be specified as an object of values or as an array of property declarations
+func (c *Config) UnmarshalJSON(b []byte) error {
+
+	ser := &struct {
+		*configAlias
+		Properties json.RawMessage `json:"properties"`
+	}{configAlias: (*configAlias)(c)}
+
+	if err := json.Unmarshal(b, ser); err != nil {
+		return err
+	}
+
+	c.Properties = nil
+	c.PropertyDefs = nil
+
+	raw := bytes.TrimSpace(ser.Properties)
+
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+
+	if raw[0] != '[' {
+		return json.Unmarshal(raw, &c.Properties)
+	}
+
+	if err := json.Unmarshal(raw, &c.PropertyDefs); err != nil {
+		return err
+	}
+
+	c.Properties = make(map[string]interface{}, len(c.PropertyDefs))
+	for _, def := range c.PropertyDefs {
+		if _, dup := c.Properties[def.Name()]; dup {
+			return fmt.Errorf("property '%s' declared more than once", def.Name())
+		}
+		c.Properties[def.Name()] = def.defaultValue()
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON
+func (c *Config) MarshalJSON() ([]byte, error) {
+
+	ser := &struct {
+		*configAlias
+		Properties interface{} `json:"properties"`
+	}{configAlias: (*configAlias)(c), Properties: c.Properties}
+
+	if c.PropertyDefs != nil {
+		ser.Properties = c.PropertyDefs
+	}
+
+	return json.Marshal(ser)
 }
 
 // defaultConfigProvider implementation of ConfigProvider
@@ -39,24 +103,34 @@ func DefaultConfigProvider() ConfigProvider {
 	return &defaultConfigProvider{}
 }
 
-// GetApp returns the app configuration
+// GetApp returns the app configuration, the configuration can either be JSON or YAML
+// and can include other descriptors
 func (d *defaultConfigProvider) GetApp() (*Config, error) {
 
 	configPath := config.GetFlogoConfigPath()
 
-	flogo, err := os.Open(configPath)
-	if err != nil {
-		return nil, err
+	if configPath == config.APP_CONFIG_LOCATION_DEFAULT {
+		configPath = findDefaultConfig()
 	}
 
-	jsonParser := json.NewDecoder(flogo)
-	app := &Config{}
-	err = jsonParser.Decode(&app)
-	if err != nil {
-		return nil, err
+	return LoadConfig(configPath)
+}
+
+// findDefaultConfig finds the default app configuration, when there is no flogo.json
+// flogo.yaml or flogo.yml is used
+func findDefaultConfig() string {
+
+	if _, err := os.Stat(config.APP_CONFIG_LOCATION_DEFAULT); err == nil {
+		return config.APP_CONFIG_LOCATION_DEFAULT
+	}
+
+	for _, configPath := range []string{"flogo.yaml", "flogo.yml"} {
+		if _, err := os.Stat(configPath); err == nil {
+			return configPath
+		}
 	}
 
-	return app, nil
+	return config.APP_CONFIG_LOCATION_DEFAULT
 }
 
 func FixUpApp(cfg *Config) {
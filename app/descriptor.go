@@ -0,0 +1,102 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// IsYAML determines if a descriptor is YAML, it is detected by the file extension and
+// when the extension is unknown by the content, JSON descriptors start with '{' or '['
+func IsYAML(fileName string, content []byte) bool {
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		return true
+	case ".json":
+		return false
+	}
+
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 {
+		return false
+	}
+
+	return trimmed[0] != '{' && trimmed[0] != '['
+}
+
+// DescriptorToJSON returns the JSON for the descriptor, YAML descriptors are converted
+// and JSON descriptors are returned as is
+func DescriptorToJSON(fileName string, content []byte) ([]byte, error) {
+
+	if !IsYAML(fileName, content) {
+		return content, nil
+	}
+
+	jsonContent, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse YAML descriptor '%s': %s", fileName, err.Error())
+	}
+
+	return jsonContent, nil
+}
+
+// UnmarshalDescriptor unmarshals a JSON or YAML descriptor into v, YAML descriptors are
+// converted to JSON first so the JSON decoding of app, trigger, handler, mapping and
+// resource configs applies to both formats
+func UnmarshalDescriptor(fileName string, content []byte, v interface{}) error {
+
+	jsonContent, err := DescriptorToJSON(fileName, content)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(jsonContent, v)
+}
+
+// ConvertToJSON converts a YAML descriptor to indented JSON, anchors and aliases are expanded
+// and comments are dropped
+func ConvertToJSON(yamlContent []byte) ([]byte, error) {
+
+	jsonContent, err := yaml.YAMLToJSON(yamlContent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert YAML descriptor: %s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, jsonContent, "", "  "); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ConvertToYAML converts a JSON descriptor to YAML
+func ConvertToYAML(jsonContent []byte) ([]byte, error) {
+
+	yamlContent, err := yaml.JSONToYAML(jsonContent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert JSON descriptor: %s", err.Error())
+	}
+
+	return yamlContent, nil
+}
+
+// MarshalConfig marshals the app config to JSON or YAML
+func MarshalConfig(cfg *Config, asYAML bool) ([]byte, error) {
+
+	jsonContent, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if !asYAML {
+		return jsonContent, nil
+	}
+
+	return ConvertToYAML(jsonContent)
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlApp = `
+# sample app
+name: sample
+type: flogo:app
+version: 0.0.1
+properties:
+  - name: port
+    type: integer
+    value: 8080
+triggers:
+  - id: rest
+    ref: github.com/flogo/trigger/rest
+    settings:
+      port: $property.port
+    handlers:
+      - settings: &getSettings
+          method: GET
+          path: /pets
+        action:
+          ref: github.com/flogo/flow
+          mappings:
+            input:
+              - type: assign
+                value: $.pathParams
+                mapTo: params
+      - settings:
+          <<: *getSettings
+          method: POST
+        action:
+          ref: github.com/flogo/flow
+resources:
+  - id: "flow:sample"
+    data:
+      name: sample
+`
+
+//TestIsYAML
+func TestIsYAML(t *testing.T) {
+
+	assert.True(t, IsYAML("flogo.yaml", []byte(`{"name":"sample"}`)))
+	assert.True(t, IsYAML("flogo.yml", nil))
+	assert.False(t, IsYAML("flogo.json", []byte("name: sample")))
+	assert.True(t, IsYAML("flogo", []byte("# comment\nname: sample")))
+	assert.False(t, IsYAML("flogo", []byte(" \n{\"name\":\"sample\"}")))
+	assert.False(t, IsYAML("flogo", []byte(" [1, 2]")))
+}
+
+//TestUnmarshalYAMLConfig
+func TestUnmarshalYAMLConfig(t *testing.T) {
+
+	cfg := &Config{}
+	err := UnmarshalDescriptor("flogo.yaml", []byte(yamlApp), cfg)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "sample", cfg.Name)
+	assert.Equal(t, 1, len(cfg.PropertyDefs))
+	assert.Equal(t, 8080, cfg.Properties["port"])
+
+	assert.Equal(t, 1, len(cfg.Triggers))
+	trg := cfg.Triggers[0]
+	assert.Equal(t, "$property.port", trg.Settings["port"])
+	assert.Equal(t, 2, len(trg.Handlers))
+
+	get := trg.Handlers[0]
+	assert.Equal(t, "GET", get.Settings["method"])
+	assert.Equal(t, data.MtAssign, get.Action.Mappings.Input[0].Type)
+	assert.Equal(t, "params", get.Action.Mappings.Input[0].MapTo)
+
+	post := trg.Handlers[1]
+	assert.Equal(t, "POST", post.Settings["method"])
+	assert.Equal(t, "/pets", post.Settings["path"])
+
+	assert.Equal(t, 1, len(cfg.Resources))
+	assert.Equal(t, "flow:sample", cfg.Resources[0].ID)
+	assert.Equal(t, `{"name":"sample"}`, string(cfg.Resources[0].Data))
+}
+
+//TestConvertRoundTrip
+func TestConvertRoundTrip(t *testing.T) {
+
+	cfg := &Config{}
+	err := UnmarshalDescriptor("flogo.yaml", []byte(yamlApp), cfg)
+	assert.Nil(t, err)
+
+	yamlContent, err := MarshalConfig(cfg, true)
+	assert.Nil(t, err)
+	assert.True(t, IsYAML("", yamlContent))
+
+	jsonContent, err := ConvertToJSON(yamlContent)
+	assert.Nil(t, err)
+
+	cfgFromYAML := &Config{}
+	err = json.Unmarshal(jsonContent, cfgFromYAML)
+	assert.Nil(t, err)
+
+	jsonContent, err = MarshalConfig(cfg, false)
+	assert.Nil(t, err)
+
+	cfgFromJSON := &Config{}
+	err = UnmarshalDescriptor("flogo.json", jsonContent, cfgFromJSON)
+	assert.Nil(t, err)
+
+	expected, _ := json.Marshal(cfg)
+	fromYAML, _ := json.Marshal(cfgFromYAML)
+	fromJSON, _ := json.Marshal(cfgFromJSON)
+
+	assert.Equal(t, string(expected), string(fromYAML))
+	assert.Equal(t, string(expected), string(fromJSON))
+
+	_, err = ConvertToJSON([]byte("name: [unclosed"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,262 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// IncludeKey is the key used to include other descriptors, paths are relative to the including descriptor.
+//
+// When an object only contains the include it is replaced by the included descriptor, within an array
+// an included array is spliced into the including array. When an object contains the include along
+// with other keys, the included descriptors are merged into it, arrays are appended and objects are
+// merged with the values of the including descriptor taking precedence.
+const IncludeKey = "$include"
+
+// LoadConfig loads the app config from the specified JSON or YAML descriptor, the descriptors
+// it includes are merged into a single app config and relative resource uris are resolved
+// against the directory of the descriptor
+func LoadConfig(configPath string) (*Config, error) {
+
+	content, err := loadIncludes(configPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonContent, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &Config{}
+	err = json.Unmarshal(jsonContent, app)
+	if err != nil {
+		return nil, err
+	}
+
+	resolveResourceURIs(app, filepath.Dir(configPath))
+
+	return app, nil
+}
+
+// resolveResourceURIs resolves the resource uris that are relative paths against the config directory
+func resolveResourceURIs(app *Config, dir string) {
+
+	for _, res := range app.Resources {
+		if res.URI == "" || strings.Contains(res.URI, "://") || filepath.IsAbs(res.URI) {
+			continue
+		}
+		res.URI = filepath.Join(dir, filepath.FromSlash(res.URI))
+	}
+}
+
+// loadIncludes loads the descriptor and resolves its includes, stack contains the descriptors
+// currently being loaded and is used to detect cycles
+func loadIncludes(descriptorPath string, stack []string) (interface{}, error) {
+
+	absPath, err := filepath.Abs(descriptorPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, path := range stack {
+		if path == absPath {
+			cycle := append(append([]string{}, stack[i:]...), absPath)
+			return nil, fmt.Errorf("include cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	content, err := ioutil.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read descriptor '%s': %s", descriptorPath, err.Error())
+	}
+
+	jsonContent, err := DescriptorToJSON(absPath, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonContent))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("unable to parse descriptor '%s': %s", descriptorPath, err.Error())
+	}
+
+	return resolveIncludes(value, filepath.Dir(absPath), append(stack, absPath))
+}
+
+func resolveIncludes(value interface{}, dir string, stack []string) (interface{}, error) {
+
+	switch t := value.(type) {
+	case map[string]interface{}:
+
+		include, hasInclude := t[IncludeKey]
+		delete(t, IncludeKey)
+
+		for key, val := range t {
+			resolved, err := resolveIncludes(val, dir, stack)
+			if err != nil {
+				return nil, err
+			}
+			t[key] = resolved
+		}
+
+		if !hasInclude {
+			return t, nil
+		}
+
+		included, err := loadIncluded(include, dir, stack)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(t) == 0 {
+			if len(included) == 1 {
+				return included[0], nil
+			}
+			return included, nil
+		}
+
+		var merged interface{} = map[string]interface{}{}
+		for _, inc := range included {
+			if merged, err = mergeDescriptors(merged, inc); err != nil {
+				return nil, err
+			}
+		}
+
+		return mergeDescriptors(merged, t)
+
+	case []interface{}:
+
+		var resolvedArr []interface{}
+
+		for _, val := range t {
+
+			obj, isObj := val.(map[string]interface{})
+			if include, ok := obj[IncludeKey]; isObj && ok && len(obj) == 1 {
+
+				included, err := loadIncluded(include, dir, stack)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, inc := range included {
+					if arr, isArr := inc.([]interface{}); isArr {
+						resolvedArr = append(resolvedArr, arr...)
+					} else {
+						resolvedArr = append(resolvedArr, inc)
+					}
+				}
+				continue
+			}
+
+			resolved, err := resolveIncludes(val, dir, stack)
+			if err != nil {
+				return nil, err
+			}
+			resolvedArr = append(resolvedArr, resolved)
+		}
+
+		return resolvedArr, nil
+	}
+
+	return value, nil
+}
+
+// loadIncluded loads the descriptors referenced by an include, which is either a path or an array of paths
+func loadIncluded(include interface{}, dir string, stack []string) ([]interface{}, error) {
+
+	var paths []string
+
+	switch t := include.(type) {
+	case string:
+		paths = []string{t}
+	case []interface{}:
+		for _, path := range t {
+			strPath, ok := path.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s '%v', expected a path", IncludeKey, path)
+			}
+			paths = append(paths, strPath)
+		}
+	default:
+		return nil, fmt.Errorf("invalid %s '%v', expected a path or an array of paths", IncludeKey, include)
+	}
+
+	var included []interface{}
+
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		content, err := loadIncludes(path, stack)
+		if err != nil {
+			return nil, err
+		}
+		included = append(included, content)
+	}
+
+	return included, nil
+}
+
+// mergeDescriptors merges the overlay into the base, arrays are appended, objects are merged
+// and otherwise the value of the overlay is used
+func mergeDescriptors(base, overlay interface{}) (interface{}, error) {
+
+	switch overlayVal := overlay.(type) {
+	case map[string]interface{}:
+
+		baseObj, ok := base.(map[string]interface{})
+		if !ok {
+			if base != nil {
+				return nil, fmt.Errorf("unable to merge object into '%v'", base)
+			}
+			return overlayVal, nil
+		}
+
+		merged := make(map[string]interface{}, len(baseObj)+len(overlayVal))
+		for key, val := range baseObj {
+			merged[key] = val
+		}
+
+		for key, val := range overlayVal {
+			baseVal, exists := merged[key]
+			if !exists {
+				merged[key] = val
+				continue
+			}
+
+			mergedVal, err := mergeDescriptors(baseVal, val)
+			if err != nil {
+				return nil, fmt.Errorf("unable to merge '%s': %s", key, err.Error())
+			}
+			merged[key] = mergedVal
+		}
+
+		return merged, nil
+
+	case []interface{}:
+
+		baseArr, ok := base.([]interface{})
+		if !ok {
+			if base != nil {
+				return nil, fmt.Errorf("unable to merge array into '%v'", base)
+			}
+			return overlayVal, nil
+		}
+
+		merged := make([]interface{}, 0, len(baseArr)+len(overlayVal))
+		merged = append(merged, baseArr...)
+
+		return append(merged, overlayVal...), nil
+	}
+
+	return overlay, nil
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeDescriptors(t *testing.T, descriptors map[string]string) string {
+
+	dir, err := ioutil.TempDir("", "include")
+	assert.Nil(t, err)
+
+	for name, content := range descriptors {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	return dir
+}
+
+//TestLoadConfigWithIncludes
+func TestLoadConfigWithIncludes(t *testing.T) {
+
+	dir := writeDescriptors(t, map[string]string{
+		"flogo.json": `{
+			"$include": ["props/common.yaml", "resources.json"],
+			"name": "sample",
+			"properties": {"port": 9090},
+			"triggers": [
+				{"id": "timer", "ref": "github.com/flogo/trigger/timer"},
+				{"$include": "triggers/rest.yaml"}
+			]
+		}`,
+		"props/common.yaml": `
+properties:
+  port: 8080
+  db.url: "postgres://localhost"
+`,
+		"resources.json":    `{"resources": [{"$include": "flows/sample.json"}, {"id": "flow:big", "uri": "flows/big.json"}]}`,
+		"flows/sample.json": `{"id": "flow:sample", "data": {"name": "sample", "retries": 12345678901234567}}`,
+		"triggers/rest.yaml": `
+- id: rest
+  ref: github.com/flogo/trigger/rest
+- id: rest2
+  ref: github.com/flogo/trigger/rest
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	cfg, err := LoadConfig(filepath.Join(dir, "flogo.json"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "sample", cfg.Name)
+	assert.Equal(t, float64(9090), cfg.Properties["port"])
+	assert.Equal(t, "postgres://localhost", cfg.Properties["db.url"])
+
+	assert.Equal(t, 3, len(cfg.Triggers))
+	assert.Equal(t, "timer", cfg.Triggers[0].Id)
+	assert.Equal(t, "rest", cfg.Triggers[1].Id)
+	assert.Equal(t, "rest2", cfg.Triggers[2].Id)
+
+	assert.Equal(t, 2, len(cfg.Resources))
+	assert.Equal(t, "flow:sample", cfg.Resources[0].ID)
+	assert.Equal(t, `{"name":"sample","retries":12345678901234567}`, string(cfg.Resources[0].Data))
+	assert.Equal(t, filepath.Join(dir, "flows", "big.json"), cfg.Resources[1].URI)
+}
+
+//TestLoadConfigIncludeCycle
+func TestLoadConfigIncludeCycle(t *testing.T) {
+
+	dir := writeDescriptors(t, map[string]string{
+		"flogo.json": `{"name": "sample", "$include": "a.json"}`,
+		"a.json":     `{"triggers": [{"$include": "b.yaml"}]}`,
+		"b.yaml":     `$include: a.json`,
+	})
+	defer os.RemoveAll(dir)
+
+	_, err := LoadConfig(filepath.Join(dir, "flogo.json"))
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "include cycle detected"))
+	assert.True(t, strings.HasSuffix(err.Error(), filepath.Join(dir, "a.json")))
+}
+
+//TestLoadConfigIncludeErrors
+func TestLoadConfigIncludeErrors(t *testing.T) {
+
+	dir := writeDescriptors(t, map[string]string{
+		"missing.json":  `{"$include": "other.json"}`,
+		"invalid.json":  `{"$include": 1}`,
+		"conflict.json": `{"$include": "props.json", "properties": [{"name": "port", "type": "integer"}]}`,
+		"props.json":    `{"properties": {"port": 8080}}`,
+	})
+	defer os.RemoveAll(dir)
+
+	_, err := LoadConfig(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+
+	_, err = LoadConfig(filepath.Join(dir, "invalid.json"))
+	assert.NotNil(t, err)
+
+	_, err = LoadConfig(filepath.Join(dir, "conflict.json"))
+	assert.NotNil(t, err)
+}
@@ -9,28 +9,42 @@ import (
 )
 
 func CreateTriggers(tConfigs []*trigger.Config, runner action.Runner) (map[string]trigger.Trigger, error) {
+	triggers, _, err := CreateTriggersWithHandlers(tConfigs, runner)
+	return triggers, err
+}
+
+// CreateTriggersWithHandlers creates the triggers and returns them along with all their handlers
+func CreateTriggersWithHandlers(tConfigs []*trigger.Config, runner action.Runner) (map[string]trigger.Trigger, []*trigger.Handler, error) {
+
+	var handlers []*trigger.Handler
 
 	triggers := make(map[string]trigger.Trigger)
 	for _, tConfig := range tConfigs {
 
 		_, exists := triggers[tConfig.Id]
 		if exists {
-			return nil, fmt.Errorf("Trigger with id '%s' already registered, trigger ids have to be unique", tConfig.Id)
+			return nil, nil, fmt.Errorf("Trigger with id '%s' already registered, trigger ids have to be unique", tConfig.Id)
 		}
 
 		triggerFactory := trigger.GetFactory(tConfig.Ref)
 
 		if triggerFactory == nil {
-			return nil, fmt.Errorf("Trigger Factory '%s' not registered", tConfig.Ref)
+			return nil, nil, fmt.Errorf("Trigger Factory '%s' not registered", tConfig.Ref)
 		}
 
 		trg := triggerFactory.New(tConfig)
 
 		if trg == nil {
-			return nil, fmt.Errorf("cannot create Trigger nil for id '%s'", tConfig.Id)
+			return nil, nil, fmt.Errorf("cannot create Trigger nil for id '%s'", tConfig.Id)
+		}
+
+		if err := tConfig.FixUp(trg.Metadata()); err != nil {
+			return nil, nil, err
 		}
 
-		tConfig.FixUp(trg.Metadata())
+		if err := tConfig.Validate(trg.Metadata()); err != nil {
+			return nil, nil, err
+		}
 
 		initCtx := &initContext{handlers: make([]*trigger.Handler, 0, len(tConfig.Handlers))}
 
@@ -48,16 +62,26 @@ func CreateTriggers(tConfigs []*trigger.Config, runner action.Runner) (map[strin
 			//create the action
 			actionFactory := action.GetFactory(hConfig.Action.Ref)
 			if actionFactory == nil {
-				return nil, fmt.Errorf("Action Factory '%s' not registered", hConfig.Action.Ref)
+				return nil, nil, fmt.Errorf("Action Factory '%s' not registered", hConfig.Action.Ref)
 			}
 
-			act, err := actionFactory.New(hConfig.Action)
+			// pin the resources the action refers to, so the handler keeps using the same version
+			actionData, err := resource.ResolveReferences(hConfig.Action.Data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Unable to resolve resources of action '%s': %s", hConfig.Action.Ref, err.Error())
+			}
+
+			actionConfig := *hConfig.Action
+			actionConfig.Data = actionData
+
+			act, err := actionFactory.New(&actionConfig)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			handler := trigger.NewHandler(hConfig, act, trg.Metadata().Output, trg.Metadata().Reply, runner)
 			initCtx.handlers = append(initCtx.handlers, handler)
+			handlers = append(handlers, handler)
 
 			if !isNew {
 				action.Register(hConfig.ActionId, act)
@@ -68,7 +92,7 @@ func CreateTriggers(tConfigs []*trigger.Config, runner action.Runner) (map[strin
 		if isNew {
 			err := newTrg.Initialize(initCtx)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		} else {
 			trg.Init(legacyRunner)
@@ -77,7 +101,7 @@ func CreateTriggers(tConfigs []*trigger.Config, runner action.Runner) (map[strin
 		triggers[tConfig.Id] = trg
 	}
 
-	return triggers, nil
+	return triggers, handlers, nil
 }
 
 func RegisterResources(rConfigs []*resource.Config) error {
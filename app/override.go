@@ -0,0 +1,98 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/TIBCOSoftware/flogo-lib/config"
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/TIBCOSoftware/flogo-lib/logger"
+)
+
+// PropertyEnvPrefix is the prefix of the environment variables used to override app properties
+const PropertyEnvPrefix = "FLOGO_APP_PROPS_"
+
+// OverrideProperties returns the app properties overlaid with the properties from the override file
+// and then from the environment, overridden values are coerced to the type of the original value
+func OverrideProperties(properties map[string]interface{}) (map[string]interface{}, error) {
+
+	overridden := make(map[string]interface{}, len(properties))
+	for name, value := range properties {
+		overridden[name] = value
+	}
+
+	if overrideFile := config.GetPropertiesOverrideFile(); overrideFile != "" {
+
+		fileProps, err := loadPropertiesFile(overrideFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, value := range fileProps {
+			if err := overrideProperty(overridden, properties, name, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for name := range properties {
+		value, exists := os.LookupEnv(PropertyEnvName(name))
+		if !exists {
+			continue
+		}
+		if err := overrideProperty(overridden, properties, name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return overridden, nil
+}
+
+// PropertyEnvName returns the name of the environment variable used to override the specified property,
+// ex. "db.url" is overridden by "FLOGO_APP_PROPS_DB_URL"
+func PropertyEnvName(property string) string {
+
+	envName := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, property)
+
+	return PropertyEnvPrefix + strings.ToUpper(envName)
+}
+
+func overrideProperty(overridden, original map[string]interface{}, name string, value interface{}) error {
+
+	if origValue, exists := original[name]; exists && origValue != nil {
+		if dataType, err := data.GetType(origValue); err == nil {
+			coerced, err := data.CoerceToValue(value, dataType)
+			if err != nil {
+				return fmt.Errorf("unable to override property '%s': %s", name, err.Error())
+			}
+			value = coerced
+		}
+	}
+
+	logger.Debugf("Overriding property '%s'", name)
+	overridden[name] = value
+
+	return nil
+}
+
+func loadPropertiesFile(fileName string) (map[string]interface{}, error) {
+
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read properties override file '%s': %s", fileName, err.Error())
+	}
+
+	properties := make(map[string]interface{})
+	if err := UnmarshalDescriptor(fileName, content, &properties); err != nil {
+		return nil, fmt.Errorf("unable to parse properties override file '%s': %s", fileName, err.Error())
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TIBCOSoftware/flogo-lib/config"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestPropertyEnvName
+func TestPropertyEnvName(t *testing.T) {
+	assert.Equal(t, "FLOGO_APP_PROPS_DB_URL", PropertyEnvName("db.url"))
+	assert.Equal(t, "FLOGO_APP_PROPS_MAXCONN", PropertyEnvName("maxConn"))
+}
+
+//TestOverrideProperties
+func TestOverrideProperties(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "props")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	overrideFile := filepath.Join(dir, "override.yaml")
+	err = ioutil.WriteFile(overrideFile, []byte("# overrides\nport: \"9090\"\nname: fromfile\nextra: true\n"), 0600)
+	assert.Nil(t, err)
+
+	os.Setenv(config.ENV_PROPS_OVERRIDE_FILE_KEY, overrideFile)
+	os.Setenv("FLOGO_APP_PROPS_NAME", "fromenv")
+	os.Setenv("FLOGO_APP_PROPS_DEBUG", "true")
+	defer func() {
+		os.Unsetenv(config.ENV_PROPS_OVERRIDE_FILE_KEY)
+		os.Unsetenv("FLOGO_APP_PROPS_NAME")
+		os.Unsetenv("FLOGO_APP_PROPS_DEBUG")
+	}()
+
+	properties := map[string]interface{}{"port": 8080.0, "name": "original", "debug": false, "other": "same"}
+
+	overridden, err := OverrideProperties(properties)
+	assert.Nil(t, err)
+	assert.Equal(t, 9090.0, overridden["port"])
+	assert.Equal(t, "fromenv", overridden["name"])
+	assert.Equal(t, true, overridden["debug"])
+	assert.Equal(t, "same", overridden["other"])
+	assert.Equal(t, true, overridden["extra"])
+
+	// original properties are not modified
+	assert.Equal(t, "original", properties["name"])
+
+	os.Setenv("FLOGO_APP_PROPS_PORT", "notanumber")
+	defer os.Unsetenv("FLOGO_APP_PROPS_PORT")
+
+	_, err = OverrideProperties(properties)
+	assert.NotNil(t, err)
+}
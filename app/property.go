@@ -1,5 +1,13 @@
 package app
 
+import (
+	"strings"
+	"sync"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/TIBCOSoftware/flogo-lib/logger"
+)
+
 var propertyProvider *PropertyProvider
 
 func init() {
@@ -10,15 +18,112 @@ func GetPropertyProvider() *PropertyProvider {
 	return propertyProvider
 }
 
+// PropertyProvider provides the app properties, encrypted property values are decrypted
+// using the secret key and properties not configured in the app are looked up using
+// the secret resolvers in the order they were added, it is safe for concurrent use
 type PropertyProvider struct {
+	mutex      sync.RWMutex
 	properties map[string]interface{}
+	types      map[string]data.Type
+
+	secretKey       []byte
+	secretResolvers []SecretResolver
 }
 
 func (pp *PropertyProvider) GetProperty(property string) (value interface{}, exists bool) {
+	pp.mutex.RLock()
 	value, exists = pp.properties[property]
+	dataType, typed := pp.types[property]
+	secretKey := pp.secretKey
+	pp.mutex.RUnlock()
+
+	if !exists {
+		return pp.resolveSecret(property)
+	}
+
+	if strVal, ok := value.(string); ok && strings.HasPrefix(strVal, SecretPrefix) {
+		decrypted, err := DecryptSecret(secretKey, strVal)
+		if err != nil {
+			logger.Errorf("Unable to decrypt property '%s': %s", property, err.Error())
+			return nil, false
+		}
+		if !typed {
+			return decrypted, true
+		}
+		// encrypted values are coerced to the declared type once decrypted
+		coerced, err := data.CoerceToValue(decrypted, dataType)
+		if err != nil {
+			logger.Errorf("Invalid value for property '%s' of type '%s': %s", property, dataType, err.Error())
+			return nil, false
+		}
+		return coerced, true
+	}
+
 	return value, exists
 }
 
 func (pp *PropertyProvider) SetProperty(property string, value interface{}) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
 	pp.properties[property] = value
 }
+
+// SetPropertyType sets the declared type of the property, encrypted values are coerced to it once decrypted
+func (pp *PropertyProvider) SetPropertyType(property string, dataType data.Type) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	if pp.types == nil {
+		pp.types = make(map[string]data.Type)
+	}
+	pp.types[property] = dataType
+}
+
+// SetSecretKey sets the key used to decrypt encrypted property values
+func (pp *PropertyProvider) SetSecretKey(key []byte) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	pp.secretKey = key
+}
+
+// AddSecretResolver adds a SecretResolver used to resolve properties not configured in the app
+func (pp *PropertyProvider) AddSecretResolver(resolver SecretResolver) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	pp.secretResolvers = append(pp.secretResolvers, resolver)
+}
+
+// GetSecret gets the secret from the secret resolvers, it implements data.SecretProvider
+func (pp *PropertyProvider) GetSecret(name string) (value string, exists bool, err error) {
+
+	pp.mutex.RLock()
+	resolvers := pp.secretResolvers
+	pp.mutex.RUnlock()
+
+	for _, resolver := range resolvers {
+		value, exists, err = resolver.GetSecret(name)
+		if err != nil || exists {
+			return value, exists, err
+		}
+	}
+
+	return "", false, nil
+}
+
+func (pp *PropertyProvider) resolveSecret(property string) (interface{}, bool) {
+
+	value, exists, err := pp.GetSecret(property)
+	if err != nil {
+		logger.Errorf("Unable to resolve secret '%s': %s", property, err.Error())
+		return nil, false
+	}
+
+	if !exists {
+		return nil, false
+	}
+
+	return value, true
+}
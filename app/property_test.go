@@ -0,0 +1,110 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//TestEncryptDecryptSecret
+func TestEncryptDecryptSecret(t *testing.T) {
+
+	key := []byte("my-secret-key")
+
+	encrypted, err := EncryptSecret(key, "password")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, SecretPrefix))
+
+	decrypted, err := DecryptSecret(key, encrypted)
+	assert.Nil(t, err)
+	assert.Equal(t, "password", decrypted)
+
+	_, err = DecryptSecret([]byte("wrong-key"), encrypted)
+	assert.NotNil(t, err)
+
+	_, err = DecryptSecret(nil, encrypted)
+	assert.NotNil(t, err)
+}
+
+//TestPropertyProviderSecrets
+func TestPropertyProviderSecrets(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "secrets")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "dbPassword"), []byte("fromfile\n"), 0600)
+	assert.Nil(t, err)
+
+	key := []byte("my-secret-key")
+	encrypted, _ := EncryptSecret(key, "decrypted")
+
+	pp := &PropertyProvider{properties: make(map[string]interface{})}
+	pp.SetProperty("plain", "value")
+	pp.SetProperty("encrypted", encrypted)
+	pp.SetSecretKey(key)
+	pp.AddSecretResolver(NewFileSecretResolver(dir))
+
+	value, exists := pp.GetProperty("plain")
+	assert.True(t, exists)
+	assert.Equal(t, "value", value)
+
+	value, exists = pp.GetProperty("encrypted")
+	assert.True(t, exists)
+	assert.Equal(t, "decrypted", value)
+
+	value, exists = pp.GetProperty("dbPassword")
+	assert.True(t, exists)
+	assert.Equal(t, "fromfile", value)
+
+	_, exists = pp.GetProperty("missing")
+	assert.False(t, exists)
+
+	_, exists = pp.GetProperty("../secrets")
+	assert.False(t, exists)
+}
+
+//TestPropertyProviderConcurrentAccess
+func TestPropertyProviderConcurrentAccess(t *testing.T) {
+
+	pp := &PropertyProvider{properties: make(map[string]interface{})}
+	pp.SetProperty("shared", 0)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				pp.SetProperty("shared", j)
+				pp.SetProperty("prop"+strconv.Itoa(i), j)
+			}
+			pp.AddSecretResolver(NewFileSecretResolver(os.TempDir()))
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, exists := pp.GetProperty("shared")
+				assert.True(t, exists)
+				pp.GetSecret("missing")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i := 0; i < 10; i++ {
+		value, exists := pp.GetProperty("prop" + strconv.Itoa(i))
+		assert.True(t, exists)
+		assert.Equal(t, 99, value)
+	}
+}
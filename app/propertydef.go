@@ -0,0 +1,180 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+)
+
+// PropertyDef is the declaration of an app property, the value of the attribute is the default value
+type PropertyDef struct {
+	*data.Attribute
+	Required bool
+
+	hasDefault bool
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON
+func (pd *PropertyDef) MarshalJSON() ([]byte, error) {
+
+	return json.Marshal(&struct {
+		Name     string      `json:"name"`
+		Type     string      `json:"type"`
+		Value    interface{} `json:"value,omitempty"`
+		Required bool        `json:"required,omitempty"`
+	}{
+		Name:     pd.Name(),
+		Type:     pd.Type().String(),
+		Value:    pd.defaultValue(),
+		Required: pd.Required,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+func (pd *PropertyDef) UnmarshalJSON(b []byte) error {
+
+	ser := &struct {
+		Name     string      `json:"name"`
+		Type     string      `json:"type"`
+		Value    interface{} `json:"value"`
+		Required bool        `json:"required"`
+	}{}
+
+	if err := json.Unmarshal(b, ser); err != nil {
+		return err
+	}
+
+	if ser.Name == "" {
+		return fmt.Errorf("property declared without a name")
+	}
+
+	dataType := data.TypeAny
+	if ser.Type != "" {
+		var exists bool
+		dataType, exists = data.ToTypeEnum(ser.Type)
+		if !exists {
+			return fmt.Errorf("unknown data type '%s' for property '%s'", ser.Type, ser.Name)
+		}
+	}
+
+	attr, err := data.NewAttribute(ser.Name, dataType, ser.Value)
+	if err != nil {
+		return fmt.Errorf("invalid default value for property '%s': %s", ser.Name, err.Error())
+	}
+
+	pd.Attribute = attr
+	pd.Required = ser.Required
+	pd.hasDefault = ser.Value != nil
+
+	return nil
+}
+
+func (pd *PropertyDef) defaultValue() interface{} {
+	if !pd.hasDefault {
+		return nil
+	}
+	return pd.Value()
+}
+
+// ValidateProperties validates the property values against their declarations, values are coerced to the
+// declared type and defaults are applied, an error is returned if a required property has no value. Encrypted
+// values are kept as is, they are coerced by the PropertyProvider once decrypted.
+func ValidateProperties(defs []*PropertyDef, properties map[string]interface{}) (map[string]interface{}, error) {
+
+	validated := make(map[string]interface{}, len(properties))
+	for name, value := range properties {
+		validated[name] = value
+	}
+
+	var missing []string
+
+	for _, def := range defs {
+
+		value, exists := validated[def.Name()]
+
+		if !exists || value == nil {
+			if def.Required {
+				missing = append(missing, def.Name())
+				continue
+			}
+			validated[def.Name()] = def.Value()
+			continue
+		}
+
+		if strVal, ok := value.(string); ok && strings.HasPrefix(strVal, SecretPrefix) {
+			continue
+		}
+
+		coerced, err := data.CoerceToValue(value, def.Type())
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for property '%s' of type '%s': %s", def.Name(), def.Type(), err.Error())
+		}
+		validated[def.Name()] = coerced
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("required properties not set: %v", missing)
+	}
+
+	return validated, nil
+}
+
+var propertyRefRegex = regexp.MustCompile(`\$property\.([^.\[\s"\\]+)|\$property\[([^\]]+)\]|\$\{property\.([^}]+)\}`)
+
+// PropertyReferences returns the names of the properties referenced in the triggers and resources of the app
+func PropertyReferences(cfg *Config) ([]string, error) {
+
+	content, err := json.Marshal(&struct {
+		Triggers  interface{} `json:"triggers"`
+		Resources interface{} `json:"resources"`
+	}{Triggers: cfg.Triggers, Resources: cfg.Resources})
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string]bool)
+	for _, match := range propertyRefRegex.FindAllStringSubmatch(string(content), -1) {
+		for _, name := range match[1:] {
+			if name != "" {
+				refs[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// UnusedProperties returns the names of the properties that are not referenced in the app
+func UnusedProperties(cfg *Config, properties map[string]interface{}) ([]string, error) {
+
+	refs, err := PropertyReferences(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]bool, len(refs))
+	for _, name := range refs {
+		referenced[name] = true
+	}
+
+	var unused []string
+	for name := range properties {
+		if !referenced[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+
+	return unused, nil
+}
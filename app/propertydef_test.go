@@ -0,0 +1,108 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/stretchr/testify/assert"
+)
+
+const declaredPropsApp = `{
+  "name": "test",
+  "version": "1.0.0",
+  "properties": [
+    { "name": "port", "type": "integer", "value": 8080 },
+    { "name": "url", "type": "string", "required": true },
+    { "name": "debug", "type": "boolean" }
+  ],
+  "triggers": [
+    { "id": "rest", "ref": "github.com/test/rest", "settings": { "port": "$property.port" } }
+  ],
+  "resources": [
+    { "id": "flow:test", "data": { "endpoint": "${property.url}" } }
+  ]
+}`
+
+//TestConfigDeclaredProperties
+func TestConfigDeclaredProperties(t *testing.T) {
+
+	cfg := &Config{}
+	err := json.Unmarshal([]byte(declaredPropsApp), cfg)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(cfg.PropertyDefs))
+	assert.Equal(t, data.TypeInteger, cfg.PropertyDefs[0].Type())
+	assert.True(t, cfg.PropertyDefs[1].Required)
+	assert.Equal(t, 8080, cfg.Properties["port"])
+	assert.Nil(t, cfg.Properties["url"])
+
+	// required property missing
+	_, err = ValidateProperties(cfg.PropertyDefs, cfg.Properties)
+	assert.NotNil(t, err)
+
+	cfg.Properties["url"] = "http://localhost"
+	cfg.Properties["port"] = "9090"
+	props, err := ValidateProperties(cfg.PropertyDefs, cfg.Properties)
+	assert.Nil(t, err)
+	assert.Equal(t, 9090, props["port"])
+	assert.Equal(t, false, props["debug"])
+
+	cfg.Properties["port"] = "abc"
+	_, err = ValidateProperties(cfg.PropertyDefs, cfg.Properties)
+	assert.NotNil(t, err)
+
+	unused, err := UnusedProperties(cfg, props)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"debug"}, unused)
+
+	// round trip keeps the declarations
+	b, err := json.Marshal(cfg)
+	assert.Nil(t, err)
+	cfg2 := &Config{}
+	err = json.Unmarshal(b, cfg2)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(cfg2.PropertyDefs))
+	assert.Nil(t, cfg2.Properties["url"])
+}
+
+//TestConfigPropertiesMap
+func TestConfigPropertiesMap(t *testing.T) {
+
+	cfg := &Config{}
+	err := json.Unmarshal([]byte(`{"name":"test","properties":{"a":"b"}}`), cfg)
+	assert.Nil(t, err)
+	assert.Nil(t, cfg.PropertyDefs)
+	assert.Equal(t, "b", cfg.Properties["a"])
+	assert.Equal(t, "test", cfg.Name)
+}
+
+//TestValidatePropertiesEncrypted
+func TestValidatePropertiesEncrypted(t *testing.T) {
+
+	cfg := &Config{}
+	err := json.Unmarshal([]byte(`{"name":"test","properties":[{"name":"port","type":"integer","required":true}]}`), cfg)
+	assert.Nil(t, err)
+
+	key := []byte("my-secret-key")
+	encrypted, _ := EncryptSecret(key, "8080")
+
+	// encrypted values aren't coerced before they are decrypted
+	properties, err := ValidateProperties(cfg.PropertyDefs, map[string]interface{}{"port": encrypted})
+	assert.Nil(t, err)
+	assert.Equal(t, encrypted, properties["port"])
+
+	pp := &PropertyProvider{properties: make(map[string]interface{})}
+	pp.SetProperty("port", properties["port"])
+	pp.SetPropertyType("port", cfg.PropertyDefs[0].Type())
+	pp.SetSecretKey(key)
+
+	value, exists := pp.GetProperty("port")
+	assert.True(t, exists)
+	assert.Equal(t, 8080, value)
+
+	invalid, _ := EncryptSecret(key, "not a number")
+	pp.SetProperty("port", invalid)
+	_, exists = pp.GetProperty("port")
+	assert.False(t, exists)
+}
@@ -0,0 +1,70 @@
+package resource
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Decompress decompresses the data of a compressed resource. The data of a compressed resource
+// is a base64 encoded string of the resource data, which can be compressed using gzip or zstd.
+// The compression is detected from the decoded data.
+func Decompress(config *Config) ([]byte, error) {
+
+	var encoded string
+	if err := json.Unmarshal(config.Data, &encoded); err != nil {
+		return nil, errors.New("compressed resource data must be a base64 encoded string")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 resource data: %s", err.Error())
+	}
+
+	return decompressData(decoded)
+}
+
+// decompressData decompresses gzip or zstd compressed data, other data is returned as is
+func decompressData(decoded []byte) ([]byte, error) {
+
+	switch {
+	case bytes.HasPrefix(decoded, gzipMagic):
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip resource data: %s", err.Error())
+		}
+		defer reader.Close()
+
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip resource data: %s", err.Error())
+		}
+		return data, nil
+
+	case bytes.HasPrefix(decoded, zstdMagic):
+		decoder, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+
+		data, err := decoder.DecodeAll(decoded, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid zstd resource data: %s", err.Error())
+		}
+		return data, nil
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,90 @@
+package resource
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleFlow = `{"name":"sample","model":"flogo-simple","rootTask":{"id":"root","tasks":[{"id":"log","activity":{"ref":"github.com/flogo/activity/log"}}]}}`
+
+// sampleFlow compressed using gzip and base64 encoded
+const gzipFlow = `"H4sIAAAAAAACAzXMTQ5AMBAG0LvMGt33HHZiUbRMtEZ0SER6d1Op7ft+HthMsKAhmrB7CxUEmqwXcJ5mqiMWPoi4NXEF/QBOkmcQZ7EIuisqI0EzMl7Idy4f1onPyMs5NCMF9R2rv6LyIqU+pRcJEGr0iwAAAA=="`
+
+type mockManager struct {
+	loaded map[string]json.RawMessage
+}
+
+func (m *mockManager) LoadResource(config *Config) error {
+	m.loaded[config.ID] = config.Data
+	return nil
+}
+
+func (m *mockManager) GetResource(id string) interface{} {
+	return m.loaded[id]
+}
+
+func zstdFlow(t *testing.T) json.RawMessage {
+
+	encoder, err := zstd.NewWriter(nil)
+	assert.Nil(t, err)
+	defer encoder.Close()
+
+	compressed := encoder.EncodeAll([]byte(sampleFlow), nil)
+	encoded, _ := json.Marshal(base64.StdEncoding.EncodeToString(compressed))
+
+	return encoded
+}
+
+//TestDecompress
+func TestDecompress(t *testing.T) {
+
+	data, err := Decompress(&Config{ID: "flow:gzip", Compressed: true, Data: json.RawMessage(gzipFlow)})
+	assert.Nil(t, err)
+	assert.Equal(t, sampleFlow, string(data))
+
+	data, err = Decompress(&Config{ID: "flow:zstd", Compressed: true, Data: zstdFlow(t)})
+	assert.Nil(t, err)
+	assert.Equal(t, sampleFlow, string(data))
+
+	encoded, _ := json.Marshal(base64.StdEncoding.EncodeToString([]byte(sampleFlow)))
+	data, err = Decompress(&Config{ID: "flow:base64", Compressed: true, Data: encoded})
+	assert.Nil(t, err)
+	assert.Equal(t, sampleFlow, string(data))
+
+	_, err = Decompress(&Config{ID: "flow:invalid", Compressed: true, Data: json.RawMessage(sampleFlow)})
+	assert.NotNil(t, err)
+
+	_, err = Decompress(&Config{ID: "flow:invalid", Compressed: true, Data: json.RawMessage(`"not base64!"`)})
+	assert.NotNil(t, err)
+
+	truncated, _ := json.Marshal(gzipFlow[1:20])
+	_, err = Decompress(&Config{ID: "flow:invalid", Compressed: true, Data: truncated})
+	assert.NotNil(t, err)
+}
+
+//TestLoadCompressed
+func TestLoadCompressed(t *testing.T) {
+
+	manager := &mockManager{loaded: make(map[string]json.RawMessage)}
+	managers["compressed"] = manager
+	defer delete(managers, "compressed")
+
+	err := Load(&Config{ID: "compressed:gzip", Compressed: true, Data: json.RawMessage(gzipFlow)})
+	assert.Nil(t, err)
+	assert.Equal(t, sampleFlow, string(manager.loaded["compressed:gzip"]))
+
+	err = Load(&Config{ID: "compressed:zstd", Compressed: true, Data: zstdFlow(t)})
+	assert.Nil(t, err)
+	assert.Equal(t, sampleFlow, string(manager.loaded["compressed:zstd"]))
+
+	err = Load(&Config{ID: "compressed:plain", Data: json.RawMessage(sampleFlow)})
+	assert.Nil(t, err)
+	assert.Equal(t, sampleFlow, string(manager.loaded["compressed:plain"]))
+
+	err = Load(&Config{ID: "compressed:invalid", Compressed: true, Data: json.RawMessage(sampleFlow)})
+	assert.NotNil(t, err)
+}
@@ -10,4 +10,8 @@ type Config struct {
 	ID         string          `json:"id"`
 	Compressed bool            `json:"compressed"`
 	Data       json.RawMessage `json:"data"`
+
+	// URI is the location of the resource data when it isn't inlined, the data is loaded
+	// when the resource is first used and reloaded when the file changes
+	URI string `json:"uri,omitempty"`
 }
@@ -2,7 +2,10 @@ package resource
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // ResourceManager interface
@@ -10,14 +13,38 @@ type Manager interface {
 	// LoadResources tells the manager to load the specified resource set
 	LoadResource(config *Config) error
 
-	// GetResource get the resource that corresponds to the specified id
+	// GetResource get the resource that corresponds to the specified id, the id of
+	// a versioned resource includes its version so versions can be held side by side
 	GetResource(id string) interface{}
 }
 
-var managers = make(map[string]Manager)
+// Unloader is an optional interface a Manager can implement to support unloading resources
+type Unloader interface {
+	// UnloadResource tells the manager to unload the resource that corresponds to the specified id
+	UnloadResource(id string) error
+}
+
+// Lister is an optional interface a Manager can implement to support listing its resources
+type Lister interface {
+	// ListResources lists the ids of the resources loaded by the manager
+	ListResources() []string
+}
+
+// Validator is an optional interface a Manager can implement to validate resources before they are loaded
+type Validator interface {
+	// ValidateResource validates the specified resource
+	ValidateResource(config *Config) error
+}
+
+var (
+	managersMu sync.RWMutex
+	managers   = make(map[string]Manager)
+)
 
 // RegisterManager registers a resource manager for the specified type
 func RegisterManager(resourceType string, manager Manager) error {
+	managersMu.Lock()
+	defer managersMu.Unlock()
 
 	_, exists := managers[resourceType]
 
@@ -31,26 +58,158 @@ func RegisterManager(resourceType string, manager Manager) error {
 
 // GetManager gets the manager for the specified resource type
 func GetManager(resourceType string) Manager {
+	managersMu.RLock()
+	defer managersMu.RUnlock()
+
 	return managers[resourceType]
 }
 
-// Load specified resource into its corresponding Resource Manager
+// Managers gets the registered resource managers by resource type
+func Managers() map[string]Manager {
+	managersMu.RLock()
+	defer managersMu.RUnlock()
+
+	managersCopy := make(map[string]Manager, len(managers))
+
+	for resType, manager := range managers {
+		managersCopy[resType] = manager
+	}
+
+	return managersCopy
+}
+
+// Load specified resource into its corresponding Resource Manager, compressed resources
+// are decompressed before they are passed to the manager and resources with a uri are
+// read from their file, which is reloaded into the manager when it changes
 func Load(config *Config) error {
-	resType, err := GetTypeFromID(config.ID)
+
+	if err := load(config); err != nil {
+		return fmt.Errorf("unable to load resource '%s': %s", config.ID, err.Error())
+	}
+
+	return nil
+}
+
+func load(config *Config) error {
+	resType, _, version, err := ParseID(config.ID)
 	if err != nil {
 		return err
 	}
 
+	if version == LatestVersion {
+		return errors.New("a specific version is required to load a resource")
+	}
+
 	manager := GetManager(resType)
 
 	if manager == nil {
 		return errors.New("unsupported resource type: " + resType)
 	}
 
+	if err := checkVersion(config.ID); err != nil {
+		return err
+	}
+
+	if config.URI != "" {
+		if err := registerURIResource(config); err != nil {
+			return err
+		}
+
+		if err := ensureLoaded(config.ID, manager); err != nil {
+			unregisterURIResource(config.ID)
+			return err
+		}
+
+		return registerVersion(config.ID)
+	}
+
+	if config.Compressed {
+		data, err := Decompress(config)
+		if err != nil {
+			return fmt.Errorf("unable to decompress: %s", err.Error())
+		}
+
+		config = &Config{ID: config.ID, Data: data}
+	}
+
+	if err := loadResource(manager, config); err != nil {
+		return err
+	}
+
+	return registerVersion(config.ID)
+}
+
+// loadResource validates the resource if the manager supports it and then loads it
+func loadResource(manager Manager, config *Config) error {
+
+	if validator, ok := manager.(Validator); ok {
+		if err := validator.ValidateResource(config); err != nil {
+			return fmt.Errorf("invalid resource: %s", err.Error())
+		}
+	}
+
 	return manager.LoadResource(config)
 }
 
-// Get gets the specified resource, id format is {type}:{id}"
+// Unload unloads the specified resource from its corresponding Resource Manager
+func Unload(id string) error {
+
+	resType, err := GetTypeFromID(id)
+	if err != nil {
+		return err
+	}
+
+	manager := GetManager(resType)
+
+	if manager == nil {
+		return errors.New("unsupported resource type: " + resType)
+	}
+
+	unloader, ok := manager.(Unloader)
+	if !ok {
+		return errors.New("unloading resources not supported for type: " + resType)
+	}
+
+	unregisterURIResource(id)
+	unregisterVersion(id)
+
+	return unloader.UnloadResource(id)
+}
+
+// List lists the ids of the resources of the specified type
+func List(resourceType string) ([]string, error) {
+
+	manager := GetManager(resourceType)
+
+	if manager == nil {
+		return nil, errors.New("unsupported resource type: " + resourceType)
+	}
+
+	lister, ok := manager.(Lister)
+	if !ok {
+		return nil, errors.New("listing resources not supported for type: " + resourceType)
+	}
+
+	ids := lister.ListResources()
+
+	listed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		listed[id] = true
+	}
+
+	for _, id := range uriResourceIDs(resourceType) {
+		if !listed[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// Get gets the specified resource, id format is {type}:{id}[@{version}], when no version is specified
+// the latest version is used
 func Get(id string) (interface{}, error) {
 
 	resType, err := GetTypeFromID(id)
@@ -64,6 +223,12 @@ func Get(id string) (interface{}, error) {
 		return nil, errors.New("unsupported resource type: " + resType)
 	}
 
+	id = ResolveID(id)
+
+	if err := ensureLoaded(id, manager); err != nil {
+		return nil, fmt.Errorf("unable to load resource '%s': %s", id, err.Error())
+	}
+
 	return manager.GetResource(id), nil
 }
 
@@ -1,6 +1,8 @@
 package resource
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,3 +14,74 @@ func TestGetTypeFromID(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "flow", resType)
 }
+
+type lifecycleManager struct {
+	mockManager
+}
+
+func (m *lifecycleManager) UnloadResource(id string) error {
+	delete(m.loaded, id)
+	return nil
+}
+
+func (m *lifecycleManager) ListResources() []string {
+	var ids []string
+	for id := range m.loaded {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (m *lifecycleManager) ValidateResource(config *Config) error {
+	if !json.Valid(config.Data) {
+		return errors.New("invalid json")
+	}
+	return nil
+}
+
+//TestManagerLifecycle
+func TestManagerLifecycle(t *testing.T) {
+
+	manager := &lifecycleManager{mockManager{loaded: make(map[string]json.RawMessage)}}
+	managers["lifecycle"] = manager
+	defer delete(managers, "lifecycle")
+
+	assert.Nil(t, Load(&Config{ID: "lifecycle:a", Data: json.RawMessage(`{}`)}))
+	assert.Nil(t, Load(&Config{ID: "lifecycle:b", Data: json.RawMessage(`{}`)}))
+
+	err := Load(&Config{ID: "lifecycle:invalid", Data: json.RawMessage(`{`)})
+	assert.NotNil(t, err)
+	assert.Equal(t, "unable to load resource 'lifecycle:invalid': invalid resource: invalid json", err.Error())
+
+	ids, err := List("lifecycle")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"lifecycle:a", "lifecycle:b"}, ids)
+
+	assert.Nil(t, Unload("lifecycle:a"))
+
+	ids, err = List("lifecycle")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"lifecycle:b"}, ids)
+
+	_, exists := Managers()["lifecycle"]
+	assert.True(t, exists)
+}
+
+//TestManagerLifecycleUnsupported
+func TestManagerLifecycleUnsupported(t *testing.T) {
+
+	manager := &mockManager{loaded: make(map[string]json.RawMessage)}
+	managers["basic"] = manager
+	defer delete(managers, "basic")
+
+	_, err := List("basic")
+	assert.NotNil(t, err)
+
+	assert.NotNil(t, Unload("basic:a"))
+
+	_, err = List("unknown")
+	assert.NotNil(t, err)
+
+	err = Load(&Config{ID: "unknown:a"})
+	assert.Equal(t, "unable to load resource 'unknown:a': unsupported resource type: unknown", err.Error())
+}
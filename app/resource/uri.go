@@ -0,0 +1,229 @@
+package resource
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TIBCOSoftware/flogo-lib/logger"
+	"github.com/TIBCOSoftware/flogo-lib/util"
+	"github.com/ghodss/yaml"
+)
+
+// uriResource is a resource whose data is loaded from a file
+type uriResource struct {
+	mutex sync.Mutex
+
+	config  *Config
+	path    string
+	loaded  bool
+	modTime time.Time
+	size    int64
+}
+
+// URIReloadInterval is the interval at which the files of the resources with a uri are checked for
+// changes, changed resources are reloaded in the background so their managers have to support
+// loading resources while resources are being retrieved
+var URIReloadInterval = 5 * time.Second
+
+var (
+	uriResourcesMu sync.RWMutex
+	uriResources   = make(map[string]*uriResource)
+	uriWatching    bool
+)
+
+// GetFilePath gets the file path of a resource uri, the uri can either be a file url or a path
+func GetFilePath(uri string) (string, error) {
+
+	if filePath, isFileURL := util.URLStringToFilePath(uri); isFileURL {
+		return filePath, nil
+	}
+
+	if idx := strings.Index(uri, "://"); idx > 0 {
+		return "", fmt.Errorf("unsupported resource uri scheme: %s", uri[:idx])
+	}
+
+	return filepath.FromSlash(uri), nil
+}
+
+// registerURIResource registers a resource that is loaded from its uri, the file of the
+// resource is watched for changes from then on
+func registerURIResource(config *Config) error {
+
+	path, err := GetFilePath(config.URI)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	uriResourcesMu.Lock()
+	defer uriResourcesMu.Unlock()
+
+	uriResources[config.ID] = &uriResource{config: config, path: path}
+
+	if !uriWatching {
+		uriWatching = true
+		go watchURIResources(URIReloadInterval)
+	}
+
+	return nil
+}
+
+// watchURIResources periodically reloads the resources whose file has changed, it
+// stops once there are no resources with a uri left
+func watchURIResources(interval time.Duration) {
+
+	for {
+		time.Sleep(interval)
+
+		uriResourcesMu.Lock()
+		if len(uriResources) == 0 {
+			uriWatching = false
+			uriResourcesMu.Unlock()
+			return
+		}
+
+		ids := make([]string, 0, len(uriResources))
+		for id := range uriResources {
+			ids = append(ids, id)
+		}
+		uriResourcesMu.Unlock()
+
+		for _, id := range ids {
+			resType, err := GetTypeFromID(id)
+			if err != nil {
+				continue
+			}
+
+			manager := GetManager(resType)
+			if manager == nil {
+				continue
+			}
+
+			if err := ensureLoaded(id, manager); err != nil {
+				logger.Warnf("Unable to reload resource '%s': %s", id, err.Error())
+			}
+		}
+	}
+}
+
+// unregisterURIResource removes the resource from the resources loaded from a uri
+func unregisterURIResource(id string) {
+
+	uriResourcesMu.Lock()
+	defer uriResourcesMu.Unlock()
+
+	delete(uriResources, id)
+}
+
+// uriResourceIDs gets the ids of the resources of the specified type that are loaded from a uri
+func uriResourceIDs(resourceType string) []string {
+
+	uriResourcesMu.RLock()
+	defer uriResourcesMu.RUnlock()
+
+	var ids []string
+	for id := range uriResources {
+		if resType, err := GetTypeFromID(id); err == nil && resType == resourceType {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// ensureLoaded loads the resource into its manager if it is loaded from a uri and
+// hasn't been loaded yet or its file has changed since it was loaded
+func ensureLoaded(id string, manager Manager) error {
+
+	uriResourcesMu.RLock()
+	res, exists := uriResources[id]
+	uriResourcesMu.RUnlock()
+
+	if !exists {
+		return nil
+	}
+
+	res.mutex.Lock()
+	defer res.mutex.Unlock()
+
+	info, err := os.Stat(res.path)
+	if err != nil {
+		if res.loaded {
+			logger.Warnf("Unable to check resource '%s' for changes: %s", id, err.Error())
+			return nil
+		}
+		return err
+	}
+
+	if res.loaded && info.ModTime().Equal(res.modTime) && info.Size() == res.size {
+		return nil
+	}
+
+	data, err := readURIResource(res)
+	if err != nil {
+		return err
+	}
+
+	if res.loaded {
+		logger.Infof("Reloading resource '%s' from '%s'", id, res.path)
+	}
+
+	err = loadResource(manager, &Config{ID: id, Data: data})
+	if err != nil {
+		return err
+	}
+
+	res.loaded = true
+	res.modTime = info.ModTime()
+	res.size = info.Size()
+
+	return nil
+}
+
+// readURIResource reads the data of the resource, the file can either contain the data as is,
+// compressed using gzip or zstd or when the resource is compressed base64 encoded
+func readURIResource(res *uriResource) ([]byte, error) {
+
+	content, err := ioutil.ReadFile(res.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.config.Compressed {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 resource data: %s", err.Error())
+		}
+		content = decoded
+	}
+
+	data, err := decompressData(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if isYAMLPath(res.path) {
+		return yaml.YAMLToJSON(data)
+	}
+
+	return data, nil
+}
+
+// isYAMLPath determines if the file is YAML using its extension, ignoring compression extensions
+func isYAMLPath(path string) bool {
+
+	path = strings.ToLower(path)
+	path = strings.TrimSuffix(strings.TrimSuffix(path, ".gz"), ".zst")
+
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
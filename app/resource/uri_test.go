@@ -0,0 +1,117 @@
+package resource
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//TestGetFilePath
+func TestGetFilePath(t *testing.T) {
+
+	path, err := GetFilePath("file:///flows/sample.json")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.FromSlash("/flows/sample.json"), path)
+
+	path, err = GetFilePath("flows/sample.json")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.FromSlash("flows/sample.json"), path)
+
+	_, err = GetFilePath("http://example.com/flows/sample.json")
+	assert.NotNil(t, err)
+}
+
+// syncManager is a manager that supports loading resources while they are being retrieved
+type syncManager struct {
+	mutex  sync.Mutex
+	loaded map[string]json.RawMessage
+}
+
+func (m *syncManager) LoadResource(config *Config) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.loaded[config.ID] = config.Data
+	return nil
+}
+
+func (m *syncManager) GetResource(id string) interface{} {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if res, exists := m.loaded[id]; exists {
+		return res
+	}
+	return nil
+}
+
+//TestLoadFromURI
+func TestLoadFromURI(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "resources")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	jsonPath := filepath.Join(dir, "sample.json")
+	assert.Nil(t, ioutil.WriteFile(jsonPath, []byte(sampleFlow), 0644))
+
+	yamlPath := filepath.Join(dir, "sample.yaml")
+	assert.Nil(t, ioutil.WriteFile(yamlPath, []byte("name: sample\n"), 0644))
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write([]byte(sampleFlow))
+	writer.Close()
+	gzipPath := filepath.Join(dir, "sample.json.gz")
+	assert.Nil(t, ioutil.WriteFile(gzipPath, buf.Bytes(), 0644))
+
+	interval := URIReloadInterval
+	URIReloadInterval = 10 * time.Millisecond
+	defer func() { URIReloadInterval = interval }()
+
+	managers["uri"] = &syncManager{loaded: make(map[string]json.RawMessage)}
+	defer delete(managers, "uri")
+
+	assert.Nil(t, Load(&Config{ID: "uri:json", URI: "file://" + filepath.ToSlash(jsonPath)}))
+	assert.Nil(t, Load(&Config{ID: "uri:yaml", URI: yamlPath}))
+	assert.Nil(t, Load(&Config{ID: "uri:gzip", URI: gzipPath}))
+	assert.NotNil(t, Load(&Config{ID: "uri:missing", URI: filepath.Join(dir, "missing.json")}))
+
+	// a file flagged as compressed has to be base64 encoded
+	err = Load(&Config{ID: "uri:notbase64", URI: jsonPath, Compressed: true})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid base64 resource data")
+
+	// loaded into the manager when the resource is loaded
+	manager := GetManager("uri")
+	assert.Equal(t, sampleFlow, string(manager.GetResource("uri:json").(json.RawMessage)))
+	assert.Equal(t, `{"name":"sample"}`, string(manager.GetResource("uri:yaml").(json.RawMessage)))
+	assert.Equal(t, sampleFlow, string(manager.GetResource("uri:gzip").(json.RawMessage)))
+	assert.Nil(t, manager.GetResource("uri:missing"))
+	assert.Nil(t, manager.GetResource("uri:notbase64"))
+
+	res, err := Get("uri:json")
+	assert.Nil(t, err)
+	assert.Equal(t, sampleFlow, string(res.(json.RawMessage)))
+
+	// reloaded into the manager when the file changes
+	assert.Nil(t, ioutil.WriteFile(jsonPath, []byte(`{"name":"changed"}`), 0644))
+	future := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(jsonPath, future, future))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for string(manager.GetResource("uri:json").(json.RawMessage)) != `{"name":"changed"}` && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, `{"name":"changed"}`, string(manager.GetResource("uri:json").(json.RawMessage)))
+
+	for _, id := range []string{"uri:json", "uri:yaml", "uri:gzip"} {
+		unregisterURIResource(id)
+	}
+}
@@ -0,0 +1,276 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LatestVersion is the version used to refer to the latest version of a resource, which
+// is also used when no version is specified
+const LatestVersion = "latest"
+
+// UriSchemeRes is the uri scheme used to reference a resource, ex. "res://flow:order@2"
+const UriSchemeRes = "res://"
+
+var (
+	versionsMu  sync.RWMutex
+	versions    = make(map[string][]string)
+	unversioned = make(map[string]bool)
+
+	versionPattern = regexp.MustCompile(`^(latest|v?[0-9]+(\.[0-9]+)*(-[0-9A-Za-z.-]+)?)$`)
+)
+
+// ParseID parses a resource id, id format is {type}:{id}[@{version}], the version has to be "latest"
+// or a dot separated number optionally followed by a pre-release suffix, ex. "2", "1.2.0" or "v2.0.0-beta".
+// A suffix that isn't a version is part of the id, so ids like "flow:user@corp" keep their meaning.
+func ParseID(id string) (resType string, name string, version string, err error) {
+
+	resType, err = GetTypeFromID(id)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	name = id[len(resType)+1:]
+
+	if idx := strings.LastIndex(name, "@"); idx >= 0 {
+		suffix := name[idx+1:]
+
+		if suffix == "" {
+			return "", "", "", errors.New("Invalid resource version: " + id)
+		}
+
+		if versionPattern.MatchString(suffix) {
+			version = suffix
+			name = name[:idx]
+		}
+	}
+
+	if name == "" {
+		return "", "", "", errors.New("Invalid resource id: " + id)
+	}
+
+	return resType, name, version, nil
+}
+
+// ResolveID resolves the id of the resource to use, when the id has no version or refers to
+// the latest version the id of the latest loaded version is returned if there are versions loaded
+func ResolveID(id string) string {
+
+	resType, name, version, err := ParseID(id)
+	if err != nil || (version != "" && version != LatestVersion) {
+		return id
+	}
+
+	baseID := resType + ":" + name
+
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+
+	resVersions := versions[baseID]
+	if len(resVersions) == 0 {
+		return baseID
+	}
+
+	return baseID + "@" + resVersions[len(resVersions)-1]
+}
+
+// ResolveReferences pins the resource references in the JSON data to the id they resolve to, references without
+// a version or to the latest version are pinned to the latest loaded version. This allows handlers to either pin
+// a version or use the latest one, while the resource manager holds the versions side by side.
+func ResolveReferences(data json.RawMessage) (json.RawMessage, error) {
+
+	if len(data) == 0 || !bytes.Contains(data, []byte(UriSchemeRes)) {
+		return data, nil
+	}
+
+	var value interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	resolved, changed := resolveReferences(value)
+	if !changed {
+		return data, nil
+	}
+
+	return json.Marshal(resolved)
+}
+
+func resolveReferences(value interface{}) (interface{}, bool) {
+
+	switch t := value.(type) {
+	case string:
+		if strings.HasPrefix(t, UriSchemeRes) {
+			id := t[len(UriSchemeRes):]
+			if resolvedID := ResolveID(id); resolvedID != id {
+				return UriSchemeRes + resolvedID, true
+			}
+		}
+	case map[string]interface{}:
+		changed := false
+		for key, elem := range t {
+			if resolved, elemChanged := resolveReferences(elem); elemChanged {
+				t[key] = resolved
+				changed = true
+			}
+		}
+		return t, changed
+	case []interface{}:
+		changed := false
+		for i, elem := range t {
+			if resolved, elemChanged := resolveReferences(elem); elemChanged {
+				t[i] = resolved
+				changed = true
+			}
+		}
+		return t, changed
+	}
+
+	return value, false
+}
+
+// Versions gets the loaded versions of the resource in ascending order
+func Versions(id string) []string {
+
+	resType, name, _, err := ParseID(id)
+	if err != nil {
+		return nil
+	}
+
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+
+	return append([]string(nil), versions[resType+":"+name]...)
+}
+
+// checkVersion checks that the resource can be loaded, a resource can't be loaded both with
+// and without a version since the id without a version resolves to the latest version
+func checkVersion(id string) error {
+
+	resType, name, version, err := ParseID(id)
+	if err != nil {
+		return err
+	}
+
+	baseID := resType + ":" + name
+
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+
+	if version == "" && len(versions[baseID]) > 0 {
+		return fmt.Errorf("resource '%s' is already loaded with a version, it can't also be loaded without one", baseID)
+	}
+
+	if version != "" && unversioned[baseID] {
+		return fmt.Errorf("resource '%s' is already loaded without a version, it can't also be loaded with one", baseID)
+	}
+
+	return nil
+}
+
+// registerVersion registers the version of a loaded resource
+func registerVersion(id string) error {
+
+	resType, name, version, err := ParseID(id)
+	if err != nil {
+		return err
+	}
+
+	if version == LatestVersion {
+		return errors.New("Invalid resource version: " + id)
+	}
+
+	baseID := resType + ":" + name
+
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+
+	if version == "" {
+		unversioned[baseID] = true
+		return nil
+	}
+
+	for _, v := range versions[baseID] {
+		if v == version {
+			return nil
+		}
+	}
+
+	resVersions := append(versions[baseID], version)
+	sort.Slice(resVersions, func(i, j int) bool {
+		return compareVersions(resVersions[i], resVersions[j]) < 0
+	})
+	versions[baseID] = resVersions
+
+	return nil
+}
+
+// unregisterVersion unregisters the version of an unloaded resource
+func unregisterVersion(id string) {
+
+	resType, name, version, err := ParseID(id)
+	if err != nil {
+		return
+	}
+
+	baseID := resType + ":" + name
+
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+
+	if version == "" {
+		delete(unversioned, baseID)
+		return
+	}
+
+	var resVersions []string
+	for _, v := range versions[baseID] {
+		if v != version {
+			resVersions = append(resVersions, v)
+		}
+	}
+
+	if len(resVersions) == 0 {
+		delete(versions, baseID)
+	} else {
+		versions[baseID] = resVersions
+	}
+}
+
+// compareVersions compares dot separated versions, numeric parts are compared numerically
+// and other parts lexically
+func compareVersions(v1, v2 string) int {
+
+	parts1 := strings.Split(v1, ".")
+	parts2 := strings.Split(v2, ".")
+
+	for i := 0; i < len(parts1) && i < len(parts2); i++ {
+
+		n1, err1 := strconv.Atoi(parts1[i])
+		n2, err2 := strconv.Atoi(parts2[i])
+
+		switch {
+		case err1 == nil && err2 == nil:
+			if n1 != n2 {
+				if n1 < n2 {
+					return -1
+				}
+				return 1
+			}
+		case parts1[i] != parts2[i]:
+			return strings.Compare(parts1[i], parts2[i])
+		}
+	}
+
+	return len(parts1) - len(parts2)
+}
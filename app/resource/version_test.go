@@ -0,0 +1,131 @@
+package resource
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//TestParseID
+func TestParseID(t *testing.T) {
+
+	resType, name, version, err := ParseID("flow:order@2")
+	assert.Nil(t, err)
+	assert.Equal(t, "flow", resType)
+	assert.Equal(t, "order", name)
+	assert.Equal(t, "2", version)
+
+	resType, name, version, err = ParseID("flow:order")
+	assert.Nil(t, err)
+	assert.Equal(t, "flow", resType)
+	assert.Equal(t, "order", name)
+	assert.Equal(t, "", version)
+
+	_, name, version, err = ParseID("flow:order@v1.2.0-beta")
+	assert.Nil(t, err)
+	assert.Equal(t, "order", name)
+	assert.Equal(t, "v1.2.0-beta", version)
+
+	// a suffix that isn't a version is part of the id
+	_, name, version, err = ParseID("flow:user@corp")
+	assert.Nil(t, err)
+	assert.Equal(t, "user@corp", name)
+	assert.Equal(t, "", version)
+
+	_, _, _, err = ParseID("flow:order@")
+	assert.NotNil(t, err)
+
+	_, _, _, err = ParseID("flow:@2")
+	assert.NotNil(t, err)
+
+	_, _, _, err = ParseID("order")
+	assert.NotNil(t, err)
+}
+
+//TestCompareVersions
+func TestCompareVersions(t *testing.T) {
+
+	assert.True(t, compareVersions("2", "10") < 0)
+	assert.True(t, compareVersions("1.10.0", "1.9.1") > 0)
+	assert.True(t, compareVersions("1.0", "1.0.1") < 0)
+	assert.True(t, compareVersions("1.0.0-beta", "1.0.0-alpha") > 0)
+	assert.Equal(t, 0, compareVersions("1.2", "1.2"))
+}
+
+//TestVersionedResources
+func TestVersionedResources(t *testing.T) {
+
+	manager := &lifecycleManager{mockManager{loaded: make(map[string]json.RawMessage)}}
+	managers["versioned"] = manager
+	defer delete(managers, "versioned")
+
+	assert.Nil(t, Load(&Config{ID: "versioned:order@2", Data: json.RawMessage(`{"v":2}`)}))
+	assert.Nil(t, Load(&Config{ID: "versioned:order@10", Data: json.RawMessage(`{"v":10}`)}))
+	assert.Nil(t, Load(&Config{ID: "versioned:other", Data: json.RawMessage(`{"v":0}`)}))
+	assert.NotNil(t, Load(&Config{ID: "versioned:order@latest", Data: json.RawMessage(`{}`)}))
+
+	assert.Equal(t, []string{"2", "10"}, Versions("versioned:order"))
+	assert.Equal(t, "versioned:order@10", ResolveID("versioned:order"))
+	assert.Equal(t, "versioned:order@10", ResolveID("versioned:order@latest"))
+	assert.Equal(t, "versioned:order@2", ResolveID("versioned:order@2"))
+	assert.Equal(t, "versioned:other", ResolveID("versioned:other"))
+
+	// pinned and latest versions are held side by side
+	res, err := Get("versioned:order@2")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"v":2}`, string(res.(json.RawMessage)))
+
+	res, err = Get("versioned:order")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"v":10}`, string(res.(json.RawMessage)))
+
+	assert.Nil(t, Unload("versioned:order@10"))
+	assert.Equal(t, []string{"2"}, Versions("versioned:order"))
+
+	res, err = Get("versioned:order")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"v":2}`, string(res.(json.RawMessage)))
+
+	assert.Nil(t, Unload("versioned:order@2"))
+	assert.Equal(t, 0, len(Versions("versioned:order")))
+
+	// ids with a suffix that isn't a version aren't versioned
+	assert.Nil(t, Load(&Config{ID: "versioned:user@corp", Data: json.RawMessage(`{"v":1}`)}))
+	assert.Equal(t, 0, len(Versions("versioned:user@corp")))
+	assert.Equal(t, "versioned:user@corp", ResolveID("versioned:user@corp"))
+}
+
+//TestMixedVersionedResources
+func TestMixedVersionedResources(t *testing.T) {
+
+	manager := &lifecycleManager{mockManager{loaded: make(map[string]json.RawMessage)}}
+	managers["mixed"] = manager
+	defer delete(managers, "mixed")
+
+	assert.Nil(t, Load(&Config{ID: "mixed:order", Data: json.RawMessage(`{"v":0}`)}))
+
+	err := Load(&Config{ID: "mixed:order@1", Data: json.RawMessage(`{"v":1}`)})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "already loaded without a version")
+	assert.Nil(t, manager.GetResource("mixed:order@1"))
+
+	// the unversioned resource is still the one used
+	res, err := Get("mixed:order")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"v":0}`, string(res.(json.RawMessage)))
+
+	// reloading it is allowed
+	assert.Nil(t, Load(&Config{ID: "mixed:order", Data: json.RawMessage(`{"v":0}`)}))
+
+	assert.Nil(t, Load(&Config{ID: "mixed:other@1", Data: json.RawMessage(`{"v":1}`)}))
+	err = Load(&Config{ID: "mixed:other", Data: json.RawMessage(`{"v":0}`)})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "already loaded with a version")
+
+	// once unloaded the other form can be loaded
+	assert.Nil(t, Unload("mixed:order"))
+	assert.Nil(t, Load(&Config{ID: "mixed:order@1", Data: json.RawMessage(`{"v":1}`)}))
+	assert.Nil(t, Unload("mixed:order@1"))
+	assert.Nil(t, Unload("mixed:other@1"))
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TIBCOSoftware/flogo-lib/config"
+)
+
+// SecretPrefix is the prefix of encrypted property values
+const SecretPrefix = "SECRET:"
+
+// SecretResolver resolves secrets that are not stored in the app configuration
+type SecretResolver interface {
+
+	// GetSecret gets the value of the specified secret
+	GetSecret(name string) (value string, exists bool, err error)
+}
+
+// LoadSecretKey loads the key used to decrypt property values, either directly from the
+// environment or from the file specified in the environment, returns nil if not configured
+func LoadSecretKey() ([]byte, error) {
+
+	if key := config.GetSecretKey(); key != "" {
+		return []byte(key), nil
+	}
+
+	if keyFile := config.GetSecretKeyFile(); keyFile != "" {
+		key, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read secret key file '%s': %s", keyFile, err.Error())
+		}
+		return []byte(strings.TrimSpace(string(key))), nil
+	}
+
+	return nil, nil
+}
+
+// EncryptSecret encrypts the value using AES-GCM, the result is prefixed with SecretPrefix
+// so it can be used as a property value
+func EncryptSecret(key []byte, value string) (string, error) {
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+
+	return SecretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret decrypts a value encrypted with EncryptSecret
+func DecryptSecret(key []byte, encrypted string) (string, error) {
+
+	if !strings.HasPrefix(encrypted, SecretPrefix) {
+		return "", errors.New("value is not an encrypted secret")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted[len(SecretPrefix):])
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted secret: %s", err.Error())
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted secret: too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	value, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt secret: %s", err.Error())
+	}
+
+	return string(value), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+
+	if len(key) == 0 {
+		return nil, errors.New("secret key not configured")
+	}
+
+	// derive a 256 bit key from the configured key
+	hashedKey := sha256.Sum256(key)
+
+	block, err := aes.NewCipher(hashedKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// FileSecretResolver resolves secrets stored in a directory, one file per secret,
+// the file name being the name of the secret (ex. mounted secrets)
+type FileSecretResolver struct {
+	dir string
+}
+
+// NewFileSecretResolver creates a new FileSecretResolver for the specified directory
+func NewFileSecretResolver(dir string) *FileSecretResolver {
+	return &FileSecretResolver{dir: dir}
+}
+
+// GetSecret implements SecretResolver.GetSecret
+func (r *FileSecretResolver) GetSecret(name string) (string, bool, error) {
+
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", false, fmt.Errorf("invalid secret name '%s'", name)
+	}
+
+	value, err := ioutil.ReadFile(filepath.Join(r.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	return strings.TrimRight(string(value), "\r\n"), true, nil
+}
@@ -20,6 +20,12 @@ const (
 	ENV_APP_CONFIG_LOCATION_KEY  = "FLOGO_CONFIG_PATH"
 	APP_CONFIG_LOCATION_DEFAULT  = "flogo.json"
 	ENV_STOP_ENGINE_ON_ERROR_KEY = "STOP_ENGINE_ON_ERROR"
+	ENV_DEAD_LETTER_PATH_KEY     = "FLOGO_DEAD_LETTER_PATH"
+	ENV_SECRET_KEY_KEY           = "FLOGO_DATA_SECRET_KEY"
+	ENV_SECRET_KEY_FILE_KEY      = "FLOGO_DATA_SECRET_KEY_FILE"
+	ENV_SECRETS_PATH_KEY         = "FLOGO_SECRETS_PATH"
+	ENV_PROPS_OVERRIDE_FILE_KEY  = "FLOGO_PROPS_OVERRIDE_FILE"
+	ENV_SCHEMA_VALIDATION_KEY    = "FLOGO_SCHEMA_VALIDATION"
 )
 
 var defaultLogLevel = LOG_LEVEL_DEFAULT
@@ -97,3 +103,38 @@ func StopEngineOnError() bool {
 	b, _ := strconv.ParseBool(stopEngineOnError)
 	return b
 }
+
+//GetDeadLetterPath returns the path of the file used to store failed trigger events, empty if disabled
+func GetDeadLetterPath() string {
+	return os.Getenv(ENV_DEAD_LETTER_PATH_KEY)
+}
+
+//GetSecretKey returns the key used to decrypt encrypted property values
+func GetSecretKey() string {
+	return os.Getenv(ENV_SECRET_KEY_KEY)
+}
+
+//GetSecretKeyFile returns the path of the file containing the key used to decrypt encrypted property values
+func GetSecretKeyFile() string {
+	return os.Getenv(ENV_SECRET_KEY_FILE_KEY)
+}
+
+//GetSecretsPath returns the path of the directory containing the secrets, one file per secret
+func GetSecretsPath() string {
+	return os.Getenv(ENV_SECRETS_PATH_KEY)
+}
+
+//GetPropertiesOverrideFile returns the path of the JSON or YAML file used to override the app properties
+func GetPropertiesOverrideFile() string {
+	return os.Getenv(ENV_PROPS_OVERRIDE_FILE_KEY)
+}
+
+//GetSchemaValidation returns true if complex object values should be validated against their JSON Schema
+func GetSchemaValidation() bool {
+	schemaValidation := os.Getenv(ENV_SCHEMA_VALIDATION_KEY)
+	if len(schemaValidation) == 0 {
+		return false
+	}
+	b, _ := strconv.ParseBool(schemaValidation)
+	return b
+}
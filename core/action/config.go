@@ -11,6 +11,7 @@ type Config struct {
 	Ref      string           `json:"ref"`
 	Data     json.RawMessage  `json:"data"`
 	Mappings *data.IOMappings `json:"mappings"`
+	Retry    *RetryConfig     `json:"retry,omitempty"`
 
 	//Deprecated
 	Id string `json:"id"`
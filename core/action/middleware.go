@@ -0,0 +1,98 @@
+package action
+
+import (
+	"context"
+	"sync"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+)
+
+// ExecuteFunc executes the specified Action with the specified inputs
+type ExecuteFunc func(ctx context.Context, act Action, inputs map[string]*data.Attribute) (map[string]*data.Attribute, error)
+
+// Middleware wraps the execution of an Action, it can inspect or modify the inputs and
+// results, or skip the execution altogether by not calling next
+type Middleware func(next ExecuteFunc) ExecuteFunc
+
+var (
+	middlewaresMu sync.RWMutex
+	middlewares   []Middleware
+)
+
+// RegisterMiddleware registers a Middleware that is applied to every Action execution
+func RegisterMiddleware(m Middleware) {
+	middlewaresMu.Lock()
+	defer middlewaresMu.Unlock()
+
+	if m == nil {
+		panic("cannot register 'nil' middleware")
+	}
+
+	// copy on write so slices already returned by Middlewares aren't modified
+	newMiddlewares := make([]Middleware, len(middlewares), len(middlewares)+1)
+	copy(newMiddlewares, middlewares)
+
+	middlewares = append(newMiddlewares, m)
+}
+
+// Middlewares gets the globally registered middlewares
+func Middlewares() []Middleware {
+	middlewaresMu.RLock()
+	defer middlewaresMu.RUnlock()
+
+	return middlewares
+}
+
+type middlewareKey int
+
+var ctxMiddlewareKey middlewareKey
+
+// NewContextWithMiddleware returns a new Context that carries additional middlewares to apply
+// when executing an Action, they are applied after the globally registered ones
+func NewContextWithMiddleware(parentCtx context.Context, m ...Middleware) context.Context {
+	if len(m) == 0 {
+		return parentCtx
+	}
+
+	if parentCtx == nil {
+		parentCtx = context.Background()
+	}
+
+	// keep middlewares already set on the context
+	existing := contextMiddlewares(parentCtx)
+	all := make([]Middleware, 0, len(existing)+len(m))
+	all = append(all, existing...)
+	all = append(all, m...)
+
+	return context.WithValue(parentCtx, ctxMiddlewareKey, all)
+}
+
+func contextMiddlewares(ctx context.Context) []Middleware {
+	if ctx == nil {
+		return nil
+	}
+	m, _ := ctx.Value(ctxMiddlewareKey).([]Middleware)
+	return m
+}
+
+// Chain wraps the ExecuteFunc with the middlewares, the first middleware is the outermost
+func Chain(execute ExecuteFunc, m ...Middleware) ExecuteFunc {
+	for i := len(m) - 1; i >= 0; i-- {
+		execute = m[i](execute)
+	}
+	return execute
+}
+
+// ApplyMiddleware wraps the ExecuteFunc with the global middlewares followed
+// by the middlewares carried by the Context
+func ApplyMiddleware(ctx context.Context, execute ExecuteFunc) ExecuteFunc {
+
+	global := Middlewares()
+	local := contextMiddlewares(ctx)
+
+	if len(global) == 0 && len(local) == 0 {
+		return execute
+	}
+
+	return Chain(Chain(execute, local...), global...)
+}
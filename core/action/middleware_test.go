@@ -0,0 +1,106 @@
+package action
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, act Action, inputs map[string]*data.Attribute) (map[string]*data.Attribute, error) {
+			*calls = append(*calls, name)
+			return next(ctx, act, inputs)
+		}
+	}
+}
+
+//TestChain
+func TestChain(t *testing.T) {
+
+	var calls []string
+
+	execute := Chain(func(ctx context.Context, act Action, inputs map[string]*data.Attribute) (map[string]*data.Attribute, error) {
+		calls = append(calls, "action")
+		return nil, nil
+	}, recordingMiddleware("first", &calls), recordingMiddleware("second", &calls))
+
+	execute(nil, nil, nil)
+
+	assert.Equal(t, []string{"first", "second", "action"}, calls)
+}
+
+//TestApplyMiddleware
+func TestApplyMiddleware(t *testing.T) {
+
+	defer resetMiddlewares()()
+
+	var calls []string
+
+	RegisterMiddleware(recordingMiddleware("global", &calls))
+
+	ctx := NewContextWithMiddleware(context.Background(), recordingMiddleware("handler", &calls))
+	ctx = NewContextWithMiddleware(ctx, recordingMiddleware("handler2", &calls))
+
+	execute := ApplyMiddleware(ctx, func(ctx context.Context, act Action, inputs map[string]*data.Attribute) (map[string]*data.Attribute, error) {
+		calls = append(calls, "action")
+		return inputs, nil
+	})
+
+	attr, _ := data.NewAttribute("in", data.TypeString, "val")
+	results, err := execute(ctx, nil, map[string]*data.Attribute{"in": attr})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "val", results["in"].Value())
+	assert.Equal(t, []string{"global", "handler", "handler2", "action"}, calls)
+}
+
+//TestNewContextWithMiddlewareNone
+func TestNewContextWithMiddlewareNone(t *testing.T) {
+	assert.Nil(t, NewContextWithMiddleware(nil))
+}
+
+// resetMiddlewares clears the registered middlewares, returning a func that restores them
+func resetMiddlewares() func() {
+	middlewaresMu.Lock()
+	orig := middlewares
+	middlewares = nil
+	middlewaresMu.Unlock()
+
+	return func() {
+		middlewaresMu.Lock()
+		middlewares = orig
+		middlewaresMu.Unlock()
+	}
+}
+
+//TestRegisterMiddlewareConcurrent
+func TestRegisterMiddlewareConcurrent(t *testing.T) {
+
+	defer resetMiddlewares()()
+
+	noop := func(next ExecuteFunc) ExecuteFunc { return next }
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			RegisterMiddleware(noop)
+		}()
+
+		go func() {
+			defer wg.Done()
+			ApplyMiddleware(context.Background(), nil)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 10, len(Middlewares()))
+}
@@ -2,9 +2,12 @@ package action
 
 import (
 	"fmt"
+	"sync"
 )
 
 var (
+	registryMu sync.RWMutex
+
 	actionFactories = make(map[string]Factory)
 
 	//Deprecated
@@ -12,6 +15,8 @@ var (
 )
 
 func RegisterFactory(ref string, f Factory) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 
 	if len(ref) == 0 {
 		return fmt.Errorf("'ref' must be specified when registering an action factory")
@@ -31,22 +36,37 @@ func RegisterFactory(ref string, f Factory) error {
 }
 
 func GetFactory(ref string) Factory {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
 	return actionFactories[ref]
 }
 
 func Factories() map[string]Factory {
-	//todo return copy
-	return actionFactories
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factoriesCopy := make(map[string]Factory, len(actionFactories))
+
+	for ref, factory := range actionFactories {
+		factoriesCopy[ref] = factory
+	}
+
+	return factoriesCopy
 }
 
 //DEPRECATED
 func Get(id string) Action {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 
 	return actions[id]
 }
 
 //DEPRECATED
 func Register(id string, act Action) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 
 	if len(id) == 0 {
 		return fmt.Errorf("error registering action, id is empty")
@@ -67,6 +87,8 @@ func Register(id string, act Action) error {
 
 //DEPRECATED
 func Actions() map[string]Action {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 
 	actionsCopy := make(map[string]Action, len(actions))
 
@@ -0,0 +1,46 @@
+package action
+
+import (
+	"context"
+)
+
+const (
+	// BackoffFixed waits the configured interval between each attempt
+	BackoffFixed = "fixed"
+	// BackoffExponential doubles the interval after each attempt
+	BackoffExponential = "exponential"
+)
+
+// RetryConfig is the retry configuration for an Action
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of times the action is executed, including the first attempt
+	MaxAttempts int `json:"maxAttempts"`
+	// Backoff is the backoff strategy, either "fixed" (default) or "exponential"
+	Backoff string `json:"backoff,omitempty"`
+	// Interval is the interval in milliseconds before the first retry
+	Interval int `json:"interval,omitempty"`
+	// MaxInterval caps the interval in milliseconds, 0 means no cap
+	MaxInterval int `json:"maxInterval,omitempty"`
+	// Jitter is the fraction (0-1) of the interval that is randomized
+	Jitter float64 `json:"jitter,omitempty"`
+	// RetryOn is the list of error codes to retry on, if empty all errors are retried
+	RetryOn []string `json:"retryOn,omitempty"`
+}
+
+type attemptKey int
+
+var ctxAttemptKey attemptKey
+
+// NewContextWithAttempt returns a new Context that carries the execution attempt
+func NewContextWithAttempt(parentCtx context.Context, attempt int) context.Context {
+	return context.WithValue(parentCtx, ctxAttemptKey, attempt)
+}
+
+// GetAttempt returns the execution attempt (starting at 1) carried by the Context
+func GetAttempt(ctx context.Context) (int, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	attempt, ok := ctx.Value(ctxAttemptKey).(int)
+	return attempt, ok
+}
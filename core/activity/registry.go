@@ -7,8 +7,7 @@ import (
 )
 
 var (
-	//todo do we need a mutex, all currently loaded at startup
-	activitiesMu sync.Mutex
+	activitiesMu sync.RWMutex
 	activities   = make(map[string]Activity)
 )
 
@@ -43,7 +42,7 @@ func Register(activity Activity) {
 // Activities gets all the registered activities
 func Activities() []Activity {
 
-	var curActivities = activities
+	var curActivities = getActivities()
 
 	list := make([]Activity, 0, len(curActivities))
 
@@ -56,6 +55,13 @@ func Activities() []Activity {
 
 // Get gets specified activity
 func Get(id string) Activity {
-	//var curActivities = activities
-	return activities[id]
+	return getActivities()[id]
+}
+
+// getActivities gets the current activities, the map returned is never modified
+func getActivities() map[string]Activity {
+	activitiesMu.RLock()
+	defer activitiesMu.RUnlock()
+
+	return activities
 }
@@ -2,6 +2,8 @@ package activity
 
 import (
 	"fmt"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -113,3 +115,33 @@ func TestGetFactoriesOk(t *testing.T) {
 	as := Activities()
 	assert.Equal(t, 1, len(as))
 }
+
+//TestRegisterConcurrentWithGet
+func TestRegisterConcurrentWithGet(t *testing.T) {
+
+	orig := activities
+	activities = make(map[string]Activity)
+	defer func() { activities = orig }()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			Register(NewMockActivity("github.com/mock" + strconv.Itoa(i)))
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+			Get("github.com/mock" + strconv.Itoa(i))
+			Activities()
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 10, len(Activities()))
+	assert.NotNil(t, Get("github.com/mock5"))
+}
@@ -3,6 +3,8 @@ package data
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"regexp"
 )
 
 // Attribute is a simple structure used to define a data Attribute/property
@@ -10,6 +12,8 @@ type Attribute struct {
 	name     string
 	dataType Type
 	value    interface{}
+
+	metadata *AttributeMetadata
 }
 
 // NewAttribute constructs a new attribute
@@ -43,10 +47,20 @@ func CloneAttribute(name string, oldAttr *Attribute) *Attribute {
 	attr.name = name
 	attr.dataType = oldAttr.dataType
 	attr.value = oldAttr.value
+	attr.metadata = oldAttr.metadata
 
 	return &attr
 }
 
+// DeepCloneAttribute clones the given attribute assigning a new name, unlike CloneAttribute the
+// value is copied so that modifying it doesn't affect the original attribute
+func DeepCloneAttribute(name string, oldAttr *Attribute) *Attribute {
+	attr := CloneAttribute(name, oldAttr)
+	attr.value = DeepCopy(oldAttr.value)
+
+	return attr
+}
+
 func (a *Attribute) Name() string {
 	return a.name
 }
@@ -59,38 +73,118 @@ func (a *Attribute) Value() interface{} {
 	return a.value
 }
 
+// SetValue sets the value of the attribute, the value is coerced to the type of the attribute. A complex
+// object value that doesn't conform to the JSON Schema of the attribute is not assigned.
 func (a *Attribute) SetValue(value interface{}) (err error) {
-	a.value, err = CoerceToValue(value, a.dataType)
-	return err
+
+	coerced, err := CoerceToValue(value, a.dataType)
+	if err != nil {
+		a.value = coerced
+		return err
+	}
+
+	if a.dataType == TypeComplexObject {
+		coerced, err = applySchema(a.value, coerced)
+		if err != nil {
+			return fmt.Errorf("invalid '%s': %s", a.name, err.Error())
+		}
+	}
+
+	a.value = coerced
+	return nil
+}
+
+// applySchema keeps the JSON Schema of the current complex object when the new one doesn't
+// specify one, validating the new value against it if schema validation is enabled
+func applySchema(current interface{}, value interface{}) (interface{}, error) {
+
+	currentObj, ok := current.(*ComplexObject)
+	if !ok || currentObj == nil || currentObj.Metadata == "" {
+		return value, nil
+	}
+
+	newObj, ok := value.(*ComplexObject)
+	if !ok || newObj == nil || newObj.Metadata != "" {
+		return value, nil
+	}
+
+	withSchema := &ComplexObject{Metadata: currentObj.Metadata, Value: newObj.Value}
+
+	if SchemaValidationEnabled() {
+		if err := withSchema.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return withSchema, nil
+}
+
+// Metadata gets the metadata of the attribute, nil if it has none
+func (a *Attribute) Metadata() *AttributeMetadata {
+	return a.metadata
+}
+
+// SetMetadata sets the metadata of the attribute
+func (a *Attribute) SetMetadata(metadata *AttributeMetadata) {
+	a.metadata = metadata
 }
 
 // MarshalJSON implements json.Marshaler.MarshalJSON
 func (a *Attribute) MarshalJSON() ([]byte, error) {
 
-	return json.Marshal(&struct {
-		Name  string      `json:"name"`
-		Type  string      `json:"type"`
-		Value interface{} `json:"value"`
-	}{
+	ser := &attributeJSON{
 		Name:  a.name,
 		Type:  a.dataType.String(),
 		Value: a.value,
-	})
+	}
+
+	if md := a.metadata; md != nil {
+		ser.Required = md.Required
+		ser.Default = md.Default
+		ser.Allowed = md.Allowed
+		ser.Min = md.Min
+		ser.Max = md.Max
+		ser.Description = md.Description
+		if md.Pattern != nil {
+			ser.Pattern = md.Pattern.String()
+		}
+	}
+
+	return json.Marshal(ser)
+}
+
+// attributeJSON is the json representation of an attribute
+type attributeJSON struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+
+	Required    bool          `json:"required,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Allowed     []interface{} `json:"allowed,omitempty"`
+	Min         *float64      `json:"min,omitempty"`
+	Max         *float64      `json:"max,omitempty"`
+	Pattern     string        `json:"pattern,omitempty"`
+	Description string        `json:"description,omitempty"`
 }
 
 // UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
 func (a *Attribute) UnmarshalJSON(data []byte) error {
 
 	ser := &struct {
-		Name  string      `json:"name"`
-		Type  string      `json:"type"`
-		Value interface{} `json:"value"`
+		attributeJSON
+
+		//enum is an alias of allowed
+		Enum []interface{} `json:"enum"`
 	}{}
 
-	if err := json.Unmarshal(data, ser); err != nil {
+	if err := decodeJSON(data, ser); err != nil {
 		return err
 	}
 
+	ser.Value = toLosslessNumbers(ser.Value)
+	ser.Default = toLosslessNumbers(ser.Default)
+
 	a.name = ser.Name
 	dt, exists := ToTypeEnum(ser.Type)
 
@@ -107,9 +201,56 @@ func (a *Attribute) UnmarshalJSON(data []byte) error {
 		a.value = val
 	}
 
+	if len(ser.Allowed) == 0 {
+		ser.Allowed = ser.Enum
+	}
+
+	a.metadata, err = newAttributeMetadata(&ser.attributeJSON, a.dataType)
+	if err != nil {
+		return fmt.Errorf("invalid metadata for '%s': %s", a.name, err.Error())
+	}
+
 	return nil
 }
 
+// newAttributeMetadata creates the attribute metadata from its json representation, nil is
+// returned if no metadata was specified
+func newAttributeMetadata(ser *attributeJSON, dataType Type) (*AttributeMetadata, error) {
+
+	if !ser.Required && ser.Default == nil && len(ser.Allowed) == 0 && ser.Min == nil && ser.Max == nil &&
+		ser.Pattern == "" && ser.Description == "" {
+		return nil, nil
+	}
+
+	md := &AttributeMetadata{Required: ser.Required, Min: ser.Min, Max: ser.Max, Description: ser.Description}
+
+	if ser.Default != nil {
+		defaultVal, err := CoerceToValue(ser.Default, dataType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default value - %s", err.Error())
+		}
+		md.Default = defaultVal
+	}
+
+	for _, allowedVal := range ser.Allowed {
+		coerced, err := CoerceToValue(allowedVal, dataType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed value - %s", err.Error())
+		}
+		md.Allowed = append(md.Allowed, coerced)
+	}
+
+	if ser.Pattern != "" {
+		pattern, err := regexp.Compile(ser.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern - %s", err.Error())
+		}
+		md.Pattern = pattern
+	}
+
+	return md, nil
+}
+
 // ComplexObject is the value that is used when using a "COMPLEX_OBJECT" type
 type ComplexObject struct {
 	Metadata string      `json:"metadata"`
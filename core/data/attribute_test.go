@@ -0,0 +1,106 @@
+package data
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const attrsWithMetadata = `[
+  { "name": "method", "type": "string", "required": true, "allowed": ["GET", "POST"] },
+  { "name": "retries", "type": "integer", "default": "3", "min": 0, "max": 10 },
+  { "name": "code", "type": "string", "pattern": "^[A-Z]{3}$", "description": "currency code" },
+  { "name": "tags", "type": "array", "max": 2 },
+  { "name": "plain", "type": "string" }
+]`
+
+func attrsMetadata(t *testing.T) map[string]*Attribute {
+
+	var attrs []*Attribute
+	err := json.Unmarshal([]byte(attrsWithMetadata), &attrs)
+	assert.Nil(t, err)
+
+	metadata := make(map[string]*Attribute, len(attrs))
+	for _, attr := range attrs {
+		metadata[attr.Name()] = attr
+	}
+
+	return metadata
+}
+
+//TestAttributeMetadataUnmarshal
+func TestAttributeMetadataUnmarshal(t *testing.T) {
+
+	metadata := attrsMetadata(t)
+
+	md := metadata["method"].Metadata()
+	assert.True(t, md.Required)
+	assert.Equal(t, []interface{}{"GET", "POST"}, md.Allowed)
+
+	md = metadata["retries"].Metadata()
+	assert.Equal(t, 3, md.Default)
+	assert.Equal(t, float64(10), *md.Max)
+
+	assert.Equal(t, "currency code", metadata["code"].Metadata().Description)
+	assert.Nil(t, metadata["plain"].Metadata())
+
+	b, err := json.Marshal(metadata["retries"])
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"retries","type":"integer","value":0,"default":3,"min":0,"max":10}`, string(b))
+
+	attr := &Attribute{}
+	err = json.Unmarshal([]byte(`{"name": "bad", "type": "string", "pattern": "["}`), attr)
+	assert.NotNil(t, err)
+
+	err = json.Unmarshal([]byte(`{"name": "bad", "type": "integer", "enum": ["a"]}`), attr)
+	assert.NotNil(t, err)
+}
+
+//TestApplyMetadata
+func TestApplyMetadata(t *testing.T) {
+
+	metadata := attrsMetadata(t)
+
+	values, err := ApplyMetadata(metadata, map[string]interface{}{"method": "GET", "code": "USD", "other": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, values["retries"])
+	assert.Equal(t, 1, values["other"])
+
+	_, err = ApplyMetadata(metadata, map[string]interface{}{"code": "USD"})
+	assert.Equal(t, "'method' is required", err.Error())
+
+	_, err = ApplyMetadata(metadata, map[string]interface{}{"method": "PUT"})
+	assert.NotNil(t, err)
+
+	_, err = ApplyMetadata(metadata, map[string]interface{}{"method": "GET", "retries": 11})
+	assert.Equal(t, "invalid 'retries': value 11 is greater than the maximum 10", err.Error())
+
+	_, err = ApplyMetadata(metadata, map[string]interface{}{"method": "GET", "code": "usd"})
+	assert.NotNil(t, err)
+
+	_, err = ApplyMetadata(metadata, map[string]interface{}{"method": "GET", "tags": []interface{}{"a", "b", "c"}})
+	assert.Equal(t, "invalid 'tags': length 3 is greater than the maximum 2", err.Error())
+}
+
+//TestFixedScopeMetadata
+func TestFixedScopeMetadata(t *testing.T) {
+
+	scope := NewFixedScope(attrsMetadata(t))
+
+	attr, exists := scope.GetAttr("retries")
+	assert.True(t, exists)
+	assert.Equal(t, 3, attr.Value())
+
+	assert.NotNil(t, scope.SetAttrValue("retries", 20))
+	assert.Nil(t, scope.SetAttrValue("retries", 5))
+
+	assert.Equal(t, "'method' is required", scope.Validate().Error())
+
+	assert.NotNil(t, scope.SetAttrValue("method", "DELETE"))
+	_, exists = scope.GetAttr("method")
+	assert.False(t, exists)
+
+	assert.Nil(t, scope.SetAttrValue("method", "POST"))
+	assert.Nil(t, scope.Validate())
+}
@@ -0,0 +1,160 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// AttributeMetadata is the optional metadata of an Attribute that is used to validate its values
+type AttributeMetadata struct {
+	// Required indicates that a value has to be specified for the attribute
+	Required bool
+
+	// Default is the value used when no value is specified for the attribute
+	Default interface{}
+
+	// Allowed are the values allowed for the attribute, all values are allowed when empty
+	Allowed []interface{}
+
+	// Min and Max are the bounds of a number or the bounds of the length of a string or array
+	Min *float64
+	Max *float64
+
+	// Pattern is the regular expression a string value has to match
+	Pattern *regexp.Regexp
+
+	Description string
+}
+
+// Validate validates the value against the constraints of the metadata
+func (md *AttributeMetadata) Validate(value interface{}) error {
+
+	if md == nil || value == nil {
+		return nil
+	}
+
+	if len(md.Allowed) > 0 {
+		allowed := false
+		for _, allowedVal := range md.Allowed {
+			if reflect.DeepEqual(allowedVal, value) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("value '%v' is not one of the allowed values %v", value, md.Allowed)
+		}
+	}
+
+	if md.Min != nil || md.Max != nil {
+		size, isLength, ok := sizeOf(value)
+		if ok {
+			kind := "value"
+			if isLength {
+				kind = "length"
+			}
+			if md.Min != nil && size < *md.Min {
+				return fmt.Errorf("%s %v is less than the minimum %v", kind, size, *md.Min)
+			}
+			if md.Max != nil && size > *md.Max {
+				return fmt.Errorf("%s %v is greater than the maximum %v", kind, size, *md.Max)
+			}
+		}
+	}
+
+	if md.Pattern != nil {
+		if strVal, ok := value.(string); ok && !md.Pattern.MatchString(strVal) {
+			return fmt.Errorf("value '%s' does not match the pattern '%s'", strVal, md.Pattern.String())
+		}
+	}
+
+	return nil
+}
+
+// sizeOf gets the value of a number or the length of a string, array or map
+func sizeOf(value interface{}) (size float64, isLength bool, ok bool) {
+
+	switch t := value.(type) {
+	case string:
+		return float64(len([]rune(t))), true, true
+	case int, int32, int64, float32, float64, json.Number, *Decimal:
+		num, err := CoerceToNumber(t)
+		return num, false, err == nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(rv.Len()), true, true
+	}
+
+	return 0, false, false
+}
+
+// ValidateValue validates the value of the attribute against its metadata, a missing
+// value of a required attribute is an error
+func ValidateValue(attr *Attribute, value interface{}) error {
+
+	md := attr.Metadata()
+	if md == nil {
+		return nil
+	}
+
+	if md.Required && isEmpty(value) {
+		return fmt.Errorf("'%s' is required", attr.Name())
+	}
+
+	if err := md.Validate(value); err != nil {
+		return fmt.Errorf("invalid '%s': %s", attr.Name(), err.Error())
+	}
+
+	return nil
+}
+
+// ApplyMetadata applies the metadata to the values, missing values are set to their default and the
+// values are coerced to the type of their attribute and validated, values without metadata are kept as is
+func ApplyMetadata(metadata map[string]*Attribute, values map[string]interface{}) (map[string]interface{}, error) {
+
+	applied := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		applied[name] = value
+	}
+
+	var names []string
+	for name := range metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+
+		attr := metadata[name]
+		value, exists := applied[name]
+
+		if (!exists || value == nil) && attr.Metadata() != nil && attr.Metadata().Default != nil {
+			value = attr.Metadata().Default
+		}
+
+		if value != nil {
+			coerced, err := CoerceToValue(value, attr.Type())
+			if err != nil {
+				return nil, fmt.Errorf("invalid '%s': %s", name, err.Error())
+			}
+			value = coerced
+			applied[name] = value
+		}
+
+		if err := ValidateValue(attr, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return applied, nil
+}
+
+func isEmpty(value interface{}) bool {
+	return value == nil || value == ""
+}
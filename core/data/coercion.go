@@ -1,10 +1,14 @@
 package data
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // CoerceToValue coerce a value to the specified type
@@ -30,8 +34,24 @@ func CoerceToValue(value interface{}, dataType Type) (interface{}, error) {
 		coerced, err = CoerceToParams(value)
 	case TypeComplexObject:
 		coerced, err = CoerceToComplexObject(value)
+	case TypeDateTime:
+		coerced, err = CoerceToDateTime(value)
+	case TypeBytes:
+		coerced, err = CoerceToBytes(value)
+	case TypeLong:
+		coerced, err = CoerceToLong(value)
+	case TypeDecimal:
+		coerced, err = CoerceToDecimal(value)
 	case TypeAny:
 		coerced, err = CoerceToAny(value)
+	default:
+		if elemType, ok := dataType.ElemType(); ok {
+			if dataType.BaseType() == TypeArray {
+				coerced, err = CoerceToTypedArray(value, elemType)
+			} else {
+				coerced, err = CoerceToTypedMap(value, elemType)
+			}
+		}
 	}
 
 	if err != nil {
@@ -50,14 +70,22 @@ func CoerceToString(val interface{}) (string, error) {
 		return t, nil
 	case int:
 		return strconv.Itoa(t), nil
+	case int64:
+		return strconv.FormatInt(t, 10), nil
 	case float64:
 		return strconv.FormatFloat(t, 'f', -1, 64), nil
 	case json.Number:
 		return t.String(), nil
+	case *Decimal:
+		return t.String(), nil
 	case bool:
 		return strconv.FormatBool(t), nil
 	case nil:
 		return "", nil
+	case time.Time:
+		return t.Format(time.RFC3339Nano), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(t), nil
 	case map[string]interface{}:
 		b, err := json.Marshal(t)
 		if err != nil {
@@ -87,6 +115,9 @@ func CoerceToInteger(val interface{}) (int, error) {
 	case json.Number:
 		i, err := t.Int64()
 		return int(i), err
+	case *Decimal:
+		i, err := t.Int64()
+		return int(i), err
 	case string:
 		return strconv.Atoi(t)
 	case bool:
@@ -101,6 +132,70 @@ func CoerceToInteger(val interface{}) (int, error) {
 	}
 }
 
+// CoerceToLong coerce a value to an int64
+func CoerceToLong(val interface{}) (int64, error) {
+	switch t := val.(type) {
+	case int:
+		return int64(t), nil
+	case int32:
+		return int64(t), nil
+	case int64:
+		return t, nil
+	case float64:
+		return int64(t), nil
+	case json.Number:
+		return t.Int64()
+	case *Decimal:
+		return t.Int64()
+	case string:
+		return strconv.ParseInt(t, 10, 64)
+	case bool:
+		if t {
+			return 1, nil
+		}
+		return 0, nil
+	case nil:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("Unable to coerce %#v to long", val)
+	}
+}
+
+// CoerceToDecimal coerce a value to an arbitrary-precision decimal, floats are converted
+// using their shortest representation so that 0.1 becomes exactly 0.1
+func CoerceToDecimal(val interface{}) (*Decimal, error) {
+	switch t := val.(type) {
+	case *Decimal:
+		return t, nil
+	case Decimal:
+		// the value shares its internal slices with the original, so it is copied
+		d := &Decimal{}
+		d.rat.Set(&t.rat)
+		return d, nil
+	case int:
+		return NewDecimalFromInt64(int64(t)), nil
+	case int32:
+		return NewDecimalFromInt64(int64(t)), nil
+	case int64:
+		return NewDecimalFromInt64(t), nil
+	case float64:
+		return NewDecimal(strconv.FormatFloat(t, 'g', -1, 64))
+	case json.Number:
+		return NewDecimal(t.String())
+	case string:
+		return NewDecimal(t)
+	case bool:
+		if t {
+			return NewDecimalFromInt64(1), nil
+		}
+		return NewDecimalFromInt64(0), nil
+	case nil:
+		return NewDecimalFromInt64(0), nil
+	default:
+		return nil, fmt.Errorf("Unable to coerce %#v to decimal", val)
+	}
+}
+
 // CoerceToNumber coerce a value to a number
 func CoerceToNumber(val interface{}) (float64, error) {
 	switch t := val.(type) {
@@ -112,6 +207,8 @@ func CoerceToNumber(val interface{}) (float64, error) {
 		return t, nil
 	case json.Number:
 		return t.Float64()
+	case *Decimal:
+		return t.Float64(), nil
 	case string:
 		return strconv.ParseFloat(t, 64)
 	case bool:
@@ -138,8 +235,10 @@ func CoerceToBoolean(val interface{}) (bool, error) {
 	case float64:
 		return t != 0.0, nil
 	case json.Number:
-		i, err := t.Int64()
-		return i != 0, err
+		f, err := t.Float64()
+		return f != 0, err
+	case *Decimal:
+		return t.rat.Sign() != 0, nil
 	case string:
 		return strconv.ParseBool(t)
 	case nil:
@@ -162,7 +261,7 @@ func CoerceToObject(val interface{}) (map[string]interface{}, error) {
 	case string:
 		m := make(map[string]interface{})
 		if t != "" {
-			err := json.Unmarshal([]byte(t), &m)
+			err := decodeJSON([]byte(t), &m)
 			if err != nil {
 				return nil, fmt.Errorf("Unable to coerce %#v to map[string]interface{}", val)
 			}
@@ -190,7 +289,7 @@ func CoerceToArray(val interface{}) ([]interface{}, error) {
 	case string:
 		a := make([]interface{}, 0)
 		if t != "" {
-			err := json.Unmarshal([]byte(t), &a)
+			err := decodeJSON([]byte(t), &a)
 			if err != nil {
 				return nil, fmt.Errorf("Unable to coerce %#v to map[string]interface{}", val)
 			}
@@ -203,6 +302,99 @@ func CoerceToArray(val interface{}) ([]interface{}, error) {
 	}
 }
 
+// ElementError is the error returned when an element of a typed array or map can't be coerced,
+// the path points at the offending element, ex. "[2]" or "['key']"
+type ElementError struct {
+	Path string
+	Type Type
+	Err  error
+}
+
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("invalid element %s, expected %s - %s", e.Path, e.Type.String(), e.Err.Error())
+}
+
+func newElementError(path string, elemType Type, err error) error {
+
+	if elemErr, ok := err.(*ElementError); ok {
+		// nested typed array or map, report the full path of the offending element
+		return &ElementError{Path: path + elemErr.Path, Type: elemErr.Type, Err: elemErr.Err}
+	}
+
+	return &ElementError{Path: path, Type: elemType, Err: err}
+}
+
+// CoerceToTypedArray coerce a value to an array, coercing each element to the specified type
+func CoerceToTypedArray(val interface{}, elemType Type) ([]interface{}, error) {
+
+	arr, err := CoerceToArray(val)
+	if err != nil {
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, err
+		}
+		arr = make([]interface{}, rv.Len())
+		for i := range arr {
+			arr[i] = rv.Index(i).Interface()
+		}
+	}
+
+	if arr == nil {
+		return nil, nil
+	}
+
+	typed := make([]interface{}, len(arr))
+	for i, elem := range arr {
+		coerced, err := CoerceToValue(elem, elemType)
+		if err != nil {
+			return nil, newElementError("["+strconv.Itoa(i)+"]", elemType, err)
+		}
+		typed[i] = coerced
+	}
+
+	return typed, nil
+}
+
+// CoerceToTypedMap coerce a value to a map, coercing each value to the specified type
+func CoerceToTypedMap(val interface{}, elemType Type) (map[string]interface{}, error) {
+
+	var obj map[string]interface{}
+
+	if params, ok := val.(map[string]string); ok {
+		obj = make(map[string]interface{}, len(params))
+		for key, value := range params {
+			obj[key] = value
+		}
+	} else {
+		var err error
+		obj, err = CoerceToObject(val)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if obj == nil {
+		return nil, nil
+	}
+
+	var keys []string
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	typed := make(map[string]interface{}, len(obj))
+	for _, key := range keys {
+		coerced, err := CoerceToValue(obj[key], elemType)
+		if err != nil {
+			return nil, newElementError("['"+key+"']", elemType, err)
+		}
+		typed[key] = coerced
+	}
+
+	return typed, nil
+}
+
 // CoerceToArray coerce a value to an array
 func CoerceToAny(val interface{}) (interface{}, error) {
 
@@ -211,15 +403,85 @@ func CoerceToAny(val interface{}) (interface{}, error) {
 	case json.Number:
 
 		if strings.Contains(t.String(), ".") {
-			return t.Float64()
+			if f, exact := exactFloat64(t); exact {
+				return f, nil
+			}
+			// can't be represented exactly by a float64, keep it as is to not lose precision
+			return t, nil
+		} else if i, err := t.Int64(); err == nil {
+			return i, nil
 		} else {
-			return t.Int64()
+			// too large for an int64 or has an exponent, keep it as is to not lose precision
+			return t, nil
 		}
 	default:
 		return val, nil
 	}
 }
 
+// CoerceToDateTime coerce a value to a date-time, strings are parsed as RFC3339 and
+// numbers are interpreted as milliseconds since the epoch
+func CoerceToDateTime(val interface{}) (time.Time, error) {
+	switch t := val.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, nil
+		}
+		return *t, nil
+	case string:
+		if t == "" {
+			return time.Time{}, nil
+		}
+		if dt, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return dt, nil
+		}
+		if millis, err := strconv.ParseInt(t, 10, 64); err == nil {
+			return epochMillisToTime(millis), nil
+		}
+		return time.Time{}, fmt.Errorf("Unable to coerce '%s' to datetime, expected RFC3339 or epoch millis", t)
+	case int:
+		return epochMillisToTime(int64(t)), nil
+	case int64:
+		return epochMillisToTime(t), nil
+	case float64:
+		return epochMillisToTime(int64(t)), nil
+	case json.Number:
+		millis, err := t.Int64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Unable to coerce %#v to datetime", val)
+		}
+		return epochMillisToTime(millis), nil
+	case nil:
+		return time.Time{}, nil
+	default:
+		return time.Time{}, fmt.Errorf("Unable to coerce %#v to datetime", val)
+	}
+}
+
+func epochMillisToTime(millis int64) time.Time {
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC()
+}
+
+// CoerceToBytes coerce a value to bytes, strings are decoded as base64
+func CoerceToBytes(val interface{}) ([]byte, error) {
+	switch t := val.(type) {
+	case []byte:
+		return t, nil
+	case string:
+		b, err := base64.StdEncoding.DecodeString(t)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to coerce '%s' to bytes, expected base64: %s", t, err.Error())
+		}
+		return b, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("Unable to coerce %#v to bytes", val)
+	}
+}
+
 // CoerceToParams coerce a value to params
 func CoerceToParams(val interface{}) (map[string]string, error) {
 
@@ -285,6 +547,22 @@ func CoerceToParams(val interface{}) (map[string]string, error) {
 
 // CoerceToObject coerce a value to an complex object
 func CoerceToComplexObject(val interface{}) (*ComplexObject, error) {
+
+	complexObject, err := coerceToComplexObject(val)
+	if err != nil {
+		return nil, err
+	}
+
+	if SchemaValidationEnabled() {
+		if err := complexObject.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return complexObject, nil
+}
+
+func coerceToComplexObject(val interface{}) (*ComplexObject, error) {
 	//If the val is nil then just return empty struct
 	var emptyComplexObject = &ComplexObject{Value: "{}"}
 	if val == nil {
@@ -296,7 +574,7 @@ func CoerceToComplexObject(val interface{}) (*ComplexObject, error) {
 			return emptyComplexObject, nil
 		} else {
 			complexObject := &ComplexObject{}
-			err := json.Unmarshal([]byte(t), complexObject)
+			err := decodeJSON([]byte(t), complexObject)
 			if err != nil {
 				return nil, err
 
@@ -1,7 +1,9 @@
 package data
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -105,3 +107,237 @@ func TestUnmarshComplexObject(t *testing.T) {
 	assert.NotNil(t, complexObject)
 	assert.NotEqual(t, "", complexObject.Value)
 }
+
+func TestCoerceToDateTime(t *testing.T) {
+
+	expected := time.Date(2018, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	cval, err := CoerceToDateTime("2018-03-01T10:30:00Z")
+	assert.Nil(t, err)
+	assert.True(t, expected.Equal(cval))
+
+	cval, err = CoerceToDateTime(expected.UnixNano() / int64(time.Millisecond))
+	assert.Nil(t, err)
+	assert.True(t, expected.Equal(cval))
+
+	cval, err = CoerceToDateTime(float64(expected.UnixNano() / int64(time.Millisecond)))
+	assert.Nil(t, err)
+	assert.True(t, expected.Equal(cval))
+
+	cval, err = CoerceToDateTime("1519900200000")
+	assert.Nil(t, err)
+	assert.True(t, expected.Equal(cval))
+
+	cval, err = CoerceToDateTime(nil)
+	assert.Nil(t, err)
+	assert.True(t, cval.IsZero())
+
+	_, err = CoerceToDateTime("yesterday")
+	assert.NotNil(t, err)
+
+	str, _ := CoerceToString(expected)
+	assert.Equal(t, "2018-03-01T10:30:00Z", str)
+}
+
+func TestCoerceToBytes(t *testing.T) {
+
+	cval, err := CoerceToBytes("aGVsbG8=")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("hello"), cval)
+
+	cval, err = CoerceToBytes([]byte("hello"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("hello"), cval)
+
+	_, err = CoerceToBytes("not base64!")
+	assert.NotNil(t, err)
+
+	_, err = CoerceToBytes(1)
+	assert.NotNil(t, err)
+
+	str, _ := CoerceToString([]byte("hello"))
+	assert.Equal(t, "aGVsbG8=", str)
+}
+
+func TestDateTimeAndBytesAttributes(t *testing.T) {
+
+	dtType, err := GetType(time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, TypeDateTime, dtType)
+
+	bytesType, err := GetType([]byte("hello"))
+	assert.Nil(t, err)
+	assert.Equal(t, TypeBytes, bytesType)
+
+	assert.Equal(t, "datetime", TypeDateTime.String())
+	assert.Equal(t, "bytes", TypeBytes.String())
+	assert.Equal(t, "complex_object", TypeComplexObject.String())
+
+	attrs := []*Attribute{}
+	err = json.Unmarshal([]byte(`[
+		{"name": "created", "type": "datetime", "value": "2018-03-01T10:30:00Z"},
+		{"name": "payload", "type": "bytes", "value": "aGVsbG8="}
+	]`), &attrs)
+	assert.Nil(t, err)
+	assert.Equal(t, 2018, attrs[0].Value().(time.Time).Year())
+	assert.Equal(t, []byte("hello"), attrs[1].Value())
+
+	b, err := json.Marshal(attrs)
+	assert.Nil(t, err)
+	assert.Equal(t, `[{"name":"created","type":"datetime","value":"2018-03-01T10:30:00Z"},{"name":"payload","type":"bytes","value":"aGVsbG8="}]`, string(b))
+}
+
+func TestParameterizedTypes(t *testing.T) {
+
+	arrType, found := ToTypeEnum("Array< integer >")
+	assert.True(t, found)
+	assert.Equal(t, "array<integer>", arrType.String())
+	assert.Equal(t, TypeArray, arrType.BaseType())
+	assert.Equal(t, ArrayOf(TypeInteger), arrType)
+
+	elemType, ok := arrType.ElemType()
+	assert.True(t, ok)
+	assert.Equal(t, TypeInteger, elemType)
+
+	nestedType, found := ToTypeEnum("map<array<string>>")
+	assert.True(t, found)
+	assert.Equal(t, TypeObject, nestedType.BaseType())
+	assert.Equal(t, "map<array<string>>", nestedType.String())
+
+	_, found = ToTypeEnum("array<unknown>")
+	assert.False(t, found)
+	_, found = ToTypeEnum("list<string>")
+	assert.False(t, found)
+
+	_, ok = TypeString.ElemType()
+	assert.False(t, ok)
+	assert.Equal(t, TypeString, TypeString.BaseType())
+}
+
+func TestCoerceToTypedValues(t *testing.T) {
+
+	val, err := CoerceToValue([]interface{}{"1", 2.0, 3}, ArrayOf(TypeInteger))
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1, 2, 3}, val)
+
+	val, err = CoerceToValue(`["1", "2"]`, ArrayOf(TypeInteger))
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1, 2}, val)
+
+	val, err = CoerceToValue([]string{"true", "false"}, ArrayOf(TypeBoolean))
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{true, false}, val)
+
+	_, err = CoerceToValue([]interface{}{1, "abc"}, ArrayOf(TypeInteger))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid element [1], expected integer")
+
+	val, err = CoerceToValue(map[string]interface{}{"a": 1, "b": true}, MapOf(TypeString))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "1", "b": "true"}, val)
+
+	nestedType, _ := ToTypeEnum("map<array<integer>>")
+	_, err = CoerceToValue(map[string]interface{}{"ids": []interface{}{1, 2, "x"}}, nestedType)
+	elemErr, ok := err.(*ElementError)
+	assert.True(t, ok)
+	assert.Equal(t, "['ids'][2]", elemErr.Path)
+	assert.Equal(t, TypeInteger, elemErr.Type)
+
+	attr := &Attribute{}
+	err = json.Unmarshal([]byte(`{"name": "ids", "type": "array<integer>", "value": ["1", "2"]}`), attr)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1, 2}, attr.Value())
+
+	b, err := json.Marshal(attr)
+	assert.Nil(t, err)
+
+	roundTrip := &Attribute{}
+	err = json.Unmarshal(b, roundTrip)
+	assert.Nil(t, err)
+	assert.Equal(t, attr.Type(), roundTrip.Type())
+}
+
+func TestLosslessNumbers(t *testing.T) {
+
+	obj, err := CoerceToObject(`{"id": 9007199254740993, "count": 2, "ratio": 0.1}`)
+	assert.Nil(t, err)
+	assert.Equal(t, json.Number("9007199254740993"), obj["id"])
+	assert.Equal(t, 2.0, obj["count"])
+	assert.Equal(t, 0.1, obj["ratio"])
+
+	id, err := CoerceToLong(obj["id"])
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), id)
+
+	str, err := CoerceToString(obj["id"])
+	assert.Nil(t, err)
+	assert.Equal(t, "9007199254740993", str)
+
+	id, err = CoerceToLong("9223372036854775807")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9223372036854775807), id)
+
+	attr := &Attribute{}
+	err = json.Unmarshal([]byte(`{"name": "id", "type": "any", "value": 9007199254740993}`), attr)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), attr.Value())
+
+	attr = &Attribute{}
+	err = json.Unmarshal([]byte(`{"name": "amount", "type": "any", "value": 12345678901234567.89}`), attr)
+	assert.Nil(t, err)
+	assert.Equal(t, json.Number("12345678901234567.89"), attr.Value())
+
+	val, err := CoerceToAny(json.Number("0.25"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0.25, val)
+
+	longType, found := ToTypeEnum("long")
+	assert.True(t, found)
+	assert.Equal(t, TypeLong, longType)
+
+	valType, err := GetType(int64(1))
+	assert.Nil(t, err)
+	assert.Equal(t, TypeLong, valType)
+}
+
+func TestDecimal(t *testing.T) {
+
+	d, err := CoerceToDecimal("12.50")
+	assert.Nil(t, err)
+	assert.Equal(t, "12.5", d.String())
+
+	d, err = CoerceToDecimal(0.1)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.1", d.String())
+
+	d, err = CoerceToDecimal(json.Number("123456789012345678901234567890.123"))
+	assert.Nil(t, err)
+	assert.Equal(t, "123456789012345678901234567890.123", d.String())
+
+	third, err := NewDecimalFromInt64(1).Quo(NewDecimalFromInt64(3))
+	assert.Nil(t, err)
+	assert.Equal(t, "0.3333333333333333333333333333333333", third.String())
+
+	_, err = d.Quo(NewDecimalFromInt64(0))
+	assert.NotNil(t, err)
+
+	_, err = CoerceToDecimal("abc")
+	assert.NotNil(t, err)
+
+	// a decimal value is copied, so changing it doesn't change the original
+	orig := *NewDecimalFromInt64(12345)
+	d, err = CoerceToDecimal(orig)
+	assert.Nil(t, err)
+	d.rat.SetInt64(7)
+	assert.Equal(t, "12345", orig.String())
+
+	attr := &Attribute{}
+	err = json.Unmarshal([]byte(`{"name": "price", "type": "decimal", "value": 19.99}`), attr)
+	assert.Nil(t, err)
+	assert.Equal(t, TypeDecimal, attr.Type())
+	assert.Equal(t, "19.99", attr.Value().(*Decimal).String())
+
+	b, err := json.Marshal(attr)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"price","type":"decimal","value":19.99}`, string(b))
+}
@@ -0,0 +1,62 @@
+package data
+
+// DeepCopy copies the value, objects, arrays, params, complex objects and bytes are copied
+// recursively, other values are immutable and are returned as is
+func DeepCopy(value interface{}) interface{} {
+
+	switch t := value.(type) {
+	case map[string]interface{}:
+		if t == nil {
+			return t
+		}
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[key] = DeepCopy(val)
+		}
+		return m
+	case []interface{}:
+		if t == nil {
+			return t
+		}
+		a := make([]interface{}, len(t))
+		for i, val := range t {
+			a[i] = DeepCopy(val)
+		}
+		return a
+	case []map[string]interface{}:
+		if t == nil {
+			return t
+		}
+		a := make([]map[string]interface{}, len(t))
+		for i, val := range t {
+			a[i], _ = DeepCopy(val).(map[string]interface{})
+		}
+		return a
+	case map[string]string:
+		if t == nil {
+			return t
+		}
+		m := make(map[string]string, len(t))
+		for key, val := range t {
+			m[key] = val
+		}
+		return m
+	case []string:
+		if t == nil {
+			return t
+		}
+		return append([]string(nil), t...)
+	case []byte:
+		if t == nil {
+			return t
+		}
+		return append([]byte(nil), t...)
+	case *ComplexObject:
+		if t == nil {
+			return t
+		}
+		return &ComplexObject{Metadata: t.Metadata, Value: DeepCopy(t.Value)}
+	}
+
+	return value
+}
@@ -0,0 +1,246 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DecimalPrecision is the number of decimal places kept when a division doesn't have a finite decimal representation
+var DecimalPrecision = 34
+
+var errDivisionByZero = errors.New("division by zero")
+
+// Decimal is an arbitrary-precision decimal number, it is used for values like money that can't be
+// represented by a float64 without rounding
+type Decimal struct {
+	rat big.Rat
+}
+
+// NewDecimal parses a decimal number, ex. "12.50" or "-1.5e3"
+func NewDecimal(str string) (*Decimal, error) {
+
+	d := &Decimal{}
+	if _, ok := d.rat.SetString(strings.TrimSpace(str)); !ok {
+		return nil, fmt.Errorf("'%s' is not a valid decimal", str)
+	}
+
+	return d, nil
+}
+
+// NewDecimalFromInt64 creates a decimal from an int64
+func NewDecimalFromInt64(i int64) *Decimal {
+
+	d := &Decimal{}
+	d.rat.SetInt64(i)
+
+	return d
+}
+
+// Add returns the sum d+o
+func (d *Decimal) Add(o *Decimal) *Decimal {
+	r := &Decimal{}
+	r.rat.Add(&d.rat, &o.rat)
+	return r
+}
+
+// Sub returns the difference d-o
+func (d *Decimal) Sub(o *Decimal) *Decimal {
+	r := &Decimal{}
+	r.rat.Sub(&d.rat, &o.rat)
+	return r
+}
+
+// Mul returns the product d*o
+func (d *Decimal) Mul(o *Decimal) *Decimal {
+	r := &Decimal{}
+	r.rat.Mul(&d.rat, &o.rat)
+	return r
+}
+
+// Quo returns the quotient d/o, an error is returned if o is zero
+func (d *Decimal) Quo(o *Decimal) (*Decimal, error) {
+
+	if o.rat.Sign() == 0 {
+		return nil, errDivisionByZero
+	}
+
+	r := &Decimal{}
+	r.rat.Quo(&d.rat, &o.rat)
+	return r, nil
+}
+
+// Cmp compares d and o and returns -1 if d < o, 0 if d == o and +1 if d > o
+func (d *Decimal) Cmp(o *Decimal) int {
+	return d.rat.Cmp(&o.rat)
+}
+
+// IsInt indicates if the decimal is an integer
+func (d *Decimal) IsInt() bool {
+	return d.rat.IsInt()
+}
+
+// Int64 gets the decimal as an int64, an error is returned if it isn't an integer or doesn't fit in an int64
+func (d *Decimal) Int64() (int64, error) {
+
+	if !d.rat.IsInt() || !d.rat.Num().IsInt64() {
+		return 0, fmt.Errorf("decimal %s can't be represented as an int64", d.String())
+	}
+
+	return d.rat.Num().Int64(), nil
+}
+
+// Float64 gets the nearest float64 of the decimal
+func (d *Decimal) Float64() float64 {
+	f, _ := d.rat.Float64()
+	return f
+}
+
+// String formats the decimal without an exponent, non-terminating decimals are rounded to DecimalPrecision places
+func (d *Decimal) String() string {
+
+	places, exact := decimalPlaces(d.rat.Denom())
+	if !exact {
+		places = DecimalPrecision
+	}
+
+	str := d.rat.FloatString(places)
+
+	if !exact && strings.Contains(str, ".") {
+		str = strings.TrimRight(strings.TrimRight(str, "0"), ".")
+	}
+
+	return str
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON, the decimal is written as a JSON number
+func (d *Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON, both JSON numbers and strings are accepted
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+
+	str := string(bytes.Trim(b, `"`))
+
+	parsed, err := NewDecimal(str)
+	if err != nil {
+		return err
+	}
+
+	d.rat.Set(&parsed.rat)
+	return nil
+}
+
+// decimalPlaces gets the number of decimal places needed to represent a fraction with the specified
+// denominator exactly, which is only possible if the denominator only has the prime factors 2 and 5
+func decimalPlaces(denom *big.Int) (int, bool) {
+
+	n := new(big.Int).Set(denom)
+	two, five, zero := big.NewInt(2), big.NewInt(5), big.NewInt(0)
+	rem := new(big.Int)
+
+	twos, fives := 0, 0
+	for {
+		if rem.Mod(n, two).Cmp(zero) != 0 {
+			break
+		}
+		n.Quo(n, two)
+		twos++
+	}
+	for {
+		if rem.Mod(n, five).Cmp(zero) != 0 {
+			break
+		}
+		n.Quo(n, five)
+		fives++
+	}
+
+	if n.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+
+	if twos > fives {
+		return twos, true
+	}
+	return fives, true
+}
+
+// decodeJSON decodes the JSON without losing precision, numbers are decoded as float64 unless
+// they can't be represented exactly by a float64 (ex. 64-bit ids), those are kept as json.Number
+func decodeJSON(b []byte, v interface{}) error {
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	switch t := v.(type) {
+	case *interface{}:
+		*t = toLosslessNumbers(*t)
+	case *map[string]interface{}:
+		toLosslessNumbers(*t)
+	case *[]interface{}:
+		toLosslessNumbers(*t)
+	case *ComplexObject:
+		t.Value = toLosslessNumbers(t.Value)
+	}
+
+	return nil
+}
+
+// toLosslessNumbers replaces the json.Numbers that can be represented exactly by a float64 with a float64
+func toLosslessNumbers(val interface{}) interface{} {
+
+	switch t := val.(type) {
+	case json.Number:
+		if f, exact := exactFloat64(t); exact {
+			return f
+		}
+	case map[string]interface{}:
+		for key, value := range t {
+			t[key] = toLosslessNumbers(value)
+		}
+	case []interface{}:
+		for i, value := range t {
+			t[i] = toLosslessNumbers(value)
+		}
+	}
+
+	return val
+}
+
+func exactFloat64(num json.Number) (float64, bool) {
+
+	f, err := num.Float64()
+	if err != nil {
+		return 0, false
+	}
+
+	var r big.Rat
+	if _, ok := r.SetString(num.String()); !ok {
+		return 0, false
+	}
+
+	var fr big.Rat
+	if fr.SetFloat64(f) == nil {
+		return 0, false
+	}
+
+	if r.Cmp(&fr) == 0 {
+		return f, true
+	}
+
+	// decimal fractions like 0.1 are never exact, they are fine as long as the shortest
+	// representation of the float64 is the same number
+	var sr big.Rat
+	sr.SetString(strconv.FormatFloat(f, 'g', -1, 64))
+
+	return f, r.Cmp(&sr) == 0
+}
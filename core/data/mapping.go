@@ -3,6 +3,7 @@ package data
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
 )
 
 // MappingType is an enum for possible MappingDef Types
@@ -73,6 +74,38 @@ func (md *MappingDef) UnmarshalJSON(b []byte) error {
 	return err
 }
 
+// MarshalJSON implements json.Marshaler.MarshalJSON
+func (md *MappingDef) MarshalJSON() ([]byte, error) {
+
+	return json.Marshal(&struct {
+		Type  string      `json:"type"`
+		Value interface{} `json:"value"`
+		MapTo string      `json:"mapTo"`
+	}{
+		Type:  md.Type.String(),
+		Value: md.Value,
+		MapTo: md.MapTo,
+	})
+}
+
+// String returns the name of the mapping type
+func (mt MappingType) String() string {
+	switch mt {
+	case MtAssign:
+		return "assign"
+	case MtLiteral:
+		return "literal"
+	case MtExpression:
+		return "expression"
+	case MtObject:
+		return "object"
+	case MtArray:
+		return "array"
+	default:
+		return strconv.Itoa(int(mt))
+	}
+}
+
 func ConvertMappingType(mapType interface{}) (MappingType, error) {
 	strType, _ := CoerceToString(mapType)
 	switch strType {
@@ -1,240 +1,528 @@
 package data
 
 import (
-	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 )
 
-//todo consolidate and optimize code
+// PathSegment is a segment of a path, either the key of an object or the index of an array
+type PathSegment struct {
+	Key     string
+	Index   int
+	IsIndex bool
 
-func PathGetValue(value interface{}, path string) (interface{}, error) {
+	// Append indicates that a new element is appended to the array, ex. "tags[]"
+	Append bool
 
-	if path == "" {
-		return value, nil
+	// Wildcard indicates that the rest of the path applies to all elements of the array, ex. "items[*].id"
+	Wildcard bool
+}
+
+func (s *PathSegment) String() string {
+
+	switch {
+	case s.Append:
+		return "[]"
+	case s.Wildcard:
+		return "[*]"
+	case s.IsIndex:
+		return "[" + strconv.Itoa(s.Index) + "]"
+	case strings.ContainsAny(s.Key, `.[]"`):
+		return `['` + s.Key + `']`
 	}
 
-	var newVal interface{}
-	var err error
-	var newPath string
+	return "." + s.Key
+}
 
-	if strings.HasPrefix(path, ".") {
-		if objVal, ok := value.(map[string]interface{}); ok {
-			newVal, newPath, err = pathGetSetObjValue(objVal, path, nil, false)
-		} else if paramsVal, ok := value.(map[string]string); ok {
-			newVal, newPath, err = pathGetSetParamsValue(paramsVal, path, nil, false)
-		} else {
-			return nil, fmt.Errorf("unable to evaluate path: %s", path)
-		}
-	} else if strings.HasPrefix(path, `["`) {
-		if objVal, ok := value.(map[string]interface{}); ok {
-			newVal, newPath, err = pathGetSetMapValue(objVal, path, nil, false)
-		} else if paramsVal, ok := value.(map[string]string); ok {
-			newVal, newPath, err = pathGetSetMapParamsValue(paramsVal, path, nil, false)
-		} else {
-			return nil, fmt.Errorf("unable to evaluate path: %s", path)
-		}
-	} else if strings.HasPrefix(path, "[") {
-		newVal, newPath, err = pathGetSetArrayValue(value, path, nil, false)
-	} else {
-		return nil, fmt.Errorf("unable to evaluate path: %s", path)
+// Path is a parsed path used to get, set and delete values in objects and arrays
+type Path []*PathSegment
+
+func (p Path) String() string {
+
+	var b strings.Builder
+	for _, segment := range p {
+		b.WriteString(segment.String())
 	}
 
-	if err != nil {
-		return nil, err
+	return b.String()
+}
+
+// ParsePath parses a path, keys are specified as ".key", `["key"]` or `['key']` and array elements as "[0]".
+// Negative indexes count from the end of the array, "[]" appends to the array and "[*]" applies the rest of
+// the path to all elements of the array. The leading '.' of the first key is optional.
+func ParsePath(path string) (Path, error) {
+
+	var p Path
+
+	i := 0
+	for i < len(path) {
+
+		switch path[i] {
+		case '.':
+			key, next := readPathKey(path, i+1)
+			if key == "" {
+				return nil, fmt.Errorf("invalid path '%s': empty key at position %d", path, i)
+			}
+			p = append(p, &PathSegment{Key: key})
+			i = next
+		case '[':
+			segment, next, err := readPathBracket(path, i)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, segment)
+			i = next
+		default:
+			if i > 0 {
+				return nil, fmt.Errorf("invalid path '%s': unexpected '%c' at position %d", path, path[i], i)
+			}
+			key, next := readPathKey(path, i)
+			p = append(p, &PathSegment{Key: key})
+			i = next
+		}
 	}
-	return PathGetValue(newVal, newPath)
+
+	return p, nil
 }
 
-func PathSetValue(attrValue interface{}, path string, value interface{}) error {
-	if path == "" || attrValue == nil {
-		return nil
+func readPathKey(path string, start int) (string, int) {
+
+	end := start
+	for end < len(path) && path[end] != '.' && path[end] != '[' {
+		end++
 	}
 
-	var newVal interface{}
-	var err error
-	var newPath string
+	return path[start:end], end
+}
 
-	if strings.HasPrefix(path, ".") {
+func readPathBracket(path string, start int) (*PathSegment, int, error) {
 
-		if objVal, ok := attrValue.(map[string]interface{}); ok {
-			newVal, newPath, err = pathGetSetObjValue(objVal, path, value, true)
-		} else if paramsVal, ok := attrValue.(map[string]string); ok {
-			newVal, newPath, err = pathGetSetParamsValue(paramsVal, path, value, true)
-		} else {
-			return fmt.Errorf("Unable to evaluate path: %s", path)
-		}
-	} else if strings.HasPrefix(path, `["`) {
-		if objVal, ok := attrValue.(map[string]interface{}); ok {
-			newVal, newPath, err = pathGetSetMapValue(objVal, path, value, true)
-		} else if paramsVal, ok := attrValue.(map[string]string); ok {
-			newVal, newPath, err = pathGetSetMapParamsValue(paramsVal, path, value, true)
-		} else {
-			return fmt.Errorf("unable to evaluate path: %s", path)
+	if start+1 < len(path) && (path[start+1] == '"' || path[start+1] == '\'') {
+		quote := path[start+1]
+		closeIdx := strings.Index(path[start+2:], string(quote)+"]")
+		if closeIdx == -1 {
+			return nil, 0, fmt.Errorf("invalid path '%s': unterminated key at position %d", path, start)
 		}
+		key := path[start+2 : start+2+closeIdx]
+		return &PathSegment{Key: key}, start + 2 + closeIdx + 2, nil
+	}
+
+	closeIdx := strings.IndexByte(path[start:], ']')
+	if closeIdx == -1 {
+		return nil, 0, fmt.Errorf("invalid path '%s': missing ']' at position %d", path, start)
+	}
+
+	segment, ok := toIndexSegment(path[start+1 : start+closeIdx])
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid path '%s': invalid array index '%s'", path, path[start+1:start+closeIdx])
+	}
+
+	return segment, start + closeIdx + 1, nil
+}
 
-	} else if strings.HasPrefix(path, "[") {
-		newVal, newPath, err = pathGetSetArrayValue(attrValue, path, value, true)
-	} else {
-		return fmt.Errorf("Unable to evaluate path: %s", path)
+// toIndexSegment converts the content of brackets to an index segment, ok is false if it isn't an index
+func toIndexSegment(index string) (*PathSegment, bool) {
+
+	switch strings.TrimSpace(index) {
+	case "":
+		return &PathSegment{Append: true}, true
+	case "*":
+		return &PathSegment{Wildcard: true}, true
 	}
 
+	i, err := strconv.Atoi(strings.TrimSpace(index))
 	if err != nil {
-		return err
+		return nil, false
 	}
-	return PathSetValue(newVal, newPath, value)
+
+	return &PathSegment{Index: i, IsIndex: true}, true
 }
 
-func getObjectKey(s string) (string, int) {
-	i := 0
+// Get gets the value at the path, a missing key at the end of the path results in nil
+// while a missing key in the middle of the path is an error
+func (p Path) Get(value interface{}) (interface{}, error) {
+	return pathGet(value, p, false)
+}
 
-	for i < len(s) {
+// Lookup gets the value at the path, any missing key results in nil
+func (p Path) Lookup(value interface{}) (interface{}, error) {
+	return pathGet(value, p, true)
+}
 
-		if s[i] == '.' || s[i] == '[' {
-			return s[:i], i + 1
-		}
+// Set sets the value at the path, missing objects and arrays are created and arrays are extended as
+// needed. The root is returned since it might have been created or replaced.
+func (p Path) Set(root interface{}, value interface{}) (interface{}, error) {
+	return pathSet(root, p, value)
+}
 
-		i += 1
+// Delete deletes the value at the path, deleting a missing value is a no-op. The root is returned
+// since it is replaced when an element of a root array is deleted.
+func (p Path) Delete(root interface{}) (interface{}, error) {
+	return pathDelete(root, p)
+}
+
+func pathGet(value interface{}, path Path, lenient bool) (interface{}, error) {
+
+	for i, segment := range path {
+
+		switch {
+		case segment.Append:
+			return nil, fmt.Errorf("unable to get value of '%s': '[]' can only be used to set a value", path.String())
+
+		case segment.Wildcard:
+			arr, ok := toArray(value)
+			if !ok {
+				if value == nil && lenient {
+					return nil, nil
+				}
+				return nil, fmt.Errorf("unable to get value of '%s': %s is not an array", path.String(), describePath(path[:i]))
+			}
+			results := make([]interface{}, len(arr))
+			for j, elem := range arr {
+				result, err := pathGet(elem, path[i+1:], lenient)
+				if err != nil {
+					return nil, err
+				}
+				results[j] = result
+			}
+			return results, nil
+
+		case segment.IsIndex:
+			arr, ok := toArray(value)
+			if !ok {
+				return nil, fmt.Errorf("unable to get value of '%s': %s is not an array", path.String(), describePath(path[:i]))
+			}
+			idx, ok := resolveIndex(segment.Index, len(arr))
+			if !ok || idx >= len(arr) {
+				return nil, fmt.Errorf("unable to get value of '%s': index %d out of range", path.String(), segment.Index)
+			}
+			value = arr[idx]
+
+		default:
+			var found bool
+			switch t := value.(type) {
+			case map[string]interface{}:
+				value, found = t[segment.Key]
+			case map[string]string:
+				value, found = t[segment.Key]
+			default:
+				if lenient {
+					return nil, nil
+				}
+				return nil, fmt.Errorf("unable to get value of '%s': %s is not an object", path.String(), describePath(path[:i]))
+			}
+			if !found {
+				if lenient || i == len(path)-1 {
+					return nil, nil
+				}
+				return nil, fmt.Errorf("unable to get value of '%s': '%s' not found", path.String(), path[:i+1].String())
+			}
+		}
 	}
 
-	return s, len(s) + 1
+	return value, nil
 }
 
-func getMapKey(s string) (string, int) {
-	i := 0
+func pathSet(container interface{}, path Path, value interface{}) (interface{}, error) {
+
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	segment := path[0]
 
-	for i < len(s) {
+	if !segment.isKey() {
 
-		if s[i] == '"' {
-			return s[:i], i + 4 // [" "]
- 		}
+		var arr []interface{}
+		if container != nil {
+			var ok bool
+			arr, ok = toArray(container)
+			if !ok {
+				return nil, fmt.Errorf("unable to set %s: %T is not an array", segment.String(), container)
+			}
+		}
+
+		if segment.Wildcard {
+			for i := range arr {
+				newElem, err := pathSet(arr[i], path[1:], value)
+				if err != nil {
+					return nil, err
+				}
+				arr[i] = newElem
+			}
+			return fromArray(container, arr, segment)
+		}
+
+		idx := len(arr)
+		if segment.IsIndex {
+			var ok bool
+			idx, ok = resolveIndex(segment.Index, len(arr))
+			if !ok {
+				return nil, fmt.Errorf("unable to set %s: index out of range", segment.String())
+			}
+		}
+
+		for len(arr) <= idx {
+			arr = append(arr, nil)
+		}
+
+		newElem, err := pathSet(arr[idx], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		arr[idx] = newElem
+
+		return fromArray(container, arr, segment)
+	}
 
-		i += 1
+	switch t := container.(type) {
+	case nil:
+		newElem, err := pathSet(nil, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{segment.Key: newElem}, nil
+	case map[string]interface{}:
+		newElem, err := pathSet(t[segment.Key], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		t[segment.Key] = newElem
+		return t, nil
+	case map[string]string:
+		if len(path) > 1 {
+			return nil, fmt.Errorf("unable to set %s: params can't be nested", path.String())
+		}
+		strVal, err := CoerceToString(value)
+		if err != nil {
+			return nil, err
+		}
+		t[segment.Key] = strVal
+		return t, nil
 	}
 
-	return s, len(s) + 1
+	return nil, fmt.Errorf("unable to set %s: %T is not an object", segment.String(), container)
 }
 
-func pathGetSetArrayValue(obj interface{}, path string, value interface{}, set bool) (interface{}, string, error) {
+func pathDelete(container interface{}, path Path) (interface{}, error) {
 
-	arrValue, valid := obj.([]interface{})
-	if !valid {
-		return nil, path, errors.New("'" + path + "' not an array")
+	if len(path) == 0 || container == nil {
+		return container, nil
 	}
 
-	closeIdx := strings.Index(path, "]")
+	segment := path[0]
+	last := len(path) == 1
 
-	if closeIdx == -1 {
-		return nil, path, errors.New("'" + path + "' not an array")
+	if segment.Append {
+		return nil, fmt.Errorf("unable to delete %s: '[]' can only be used to set a value", path.String())
 	}
 
-	arrayIdx, err := strconv.Atoi(path[1:closeIdx])
-	if err != nil {
-		return nil, path, errors.New("Invalid array index: " + path[1:closeIdx])
+	if segment.isKey() {
+		switch t := container.(type) {
+		case map[string]interface{}:
+			if last {
+				delete(t, segment.Key)
+				return t, nil
+			}
+			if elem, exists := t[segment.Key]; exists {
+				newElem, err := pathDelete(elem, path[1:])
+				if err != nil {
+					return nil, err
+				}
+				t[segment.Key] = newElem
+			}
+			return t, nil
+		case map[string]string:
+			if last {
+				delete(t, segment.Key)
+			}
+			return t, nil
+		}
+		return nil, fmt.Errorf("unable to delete %s: %T is not an object", path.String(), container)
+	}
+
+	arr, ok := toArray(container)
+	if !ok {
+		return nil, fmt.Errorf("unable to delete %s: %T is not an array", path.String(), container)
 	}
 
-	if arrayIdx >= len(arrValue) {
-		return nil, path, errors.New("Array index '" + path + "' out of range.")
+	if segment.Wildcard {
+		if last {
+			return make([]interface{}, 0), nil
+		}
+		for i := range arr {
+			newElem, err := pathDelete(arr[i], path[1:])
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = newElem
+		}
+		return arr, nil
+	}
+
+	idx, ok := resolveIndex(segment.Index, len(arr))
+	if !ok || idx >= len(arr) {
+		return arr, nil
 	}
 
-	if set && closeIdx == len(path)-1 {
-		arrValue[arrayIdx] = value
-		return nil, "", nil
+	if last {
+		newArr := make([]interface{}, 0, len(arr)-1)
+		newArr = append(newArr, arr[:idx]...)
+		return append(newArr, arr[idx+1:]...), nil
+	}
+
+	newElem, err := pathDelete(arr[idx], path[1:])
+	if err != nil {
+		return nil, err
 	}
+	arr[idx] = newElem
 
-	return arrValue[arrayIdx], path[closeIdx+1:], nil
+	return arr, nil
 }
 
-func pathGetSetObjValue(objValue map[string]interface{}, path string, value interface{}, set bool) (interface{}, string, error) {
+func (s *PathSegment) isKey() bool {
+	return !s.IsIndex && !s.Append && !s.Wildcard
+}
+
+// resolveIndex resolves a negative index relative to the end of the array
+func resolveIndex(index int, length int) (int, bool) {
 
-	key, npIdx := getObjectKey(path[1:])
-	if set && key == path[1:] {
-		//end of path so set the value
-		objValue[key] = value
-		return nil, "", nil
+	if index < 0 {
+		index += length
 	}
 
-	val, found := objValue[key]
+	return index, index >= 0
+}
 
-	if !found {
-		if path == "."+key {
-			return nil, "", nil
-		}
+func toArray(value interface{}) ([]interface{}, bool) {
 
-		return nil, "", errors.New("Invalid path '" + path + "'. path not found.")
+	switch t := value.(type) {
+	case []interface{}:
+		return t, true
+	case []map[string]interface{}:
+		arr := make([]interface{}, len(t))
+		for i, v := range t {
+			arr[i] = v
+		}
+		return arr, true
 	}
 
-	return val, path[npIdx:], nil
+	return nil, false
 }
 
-func pathGetSetParamsValue(params map[string]string, path string, value interface{}, set bool) (interface{}, string, error) {
+// fromArray converts the array back to the type of the original container, so that setting an
+// element of a []map[string]interface{} keeps its type and updates it in place when possible
+func fromArray(container interface{}, arr []interface{}, segment *PathSegment) (interface{}, error) {
 
-	key, _ := getObjectKey(path[1:])
-	if set && key == path[1:] {
-		//end of path so set the value
-		paramVal, err := CoerceToString(value)
+	orig, ok := container.([]map[string]interface{})
+	if !ok {
+		return arr, nil
+	}
 
-		if err != nil {
-			return nil, "", err
+	for _, elem := range arr {
+		if _, ok := elem.(map[string]interface{}); !ok && elem != nil {
+			return nil, fmt.Errorf("unable to set %s: %T elements must be objects", segment.String(), container)
 		}
-		params[key] = paramVal
-		return nil, "", nil
 	}
 
-	val, found := params[key]
+	maps := orig
+	if len(arr) != len(orig) {
+		maps = make([]map[string]interface{}, len(arr))
+	}
 
-	if !found {
-		return nil, "", errors.New("Invalid path '" + path + "'. path not found.")
+	for i, elem := range arr {
+		maps[i], _ = elem.(map[string]interface{})
 	}
 
-	return val, "", nil
+	return maps, nil
 }
 
-func pathGetSetMapValue(objValue map[string]interface{}, path string, value interface{}, set bool) (interface{}, string, error) {
+func describePath(p Path) string {
 
-	key, npIdx := getMapKey(path[2:])
-
-	if set && key + `"]` == path[2:] {
-		//end of path so set the value
-		objValue[key] = value
-		return nil, "", nil
+	if len(p) == 0 {
+		return "root"
 	}
 
-	val, found := objValue[key]
+	return "'" + p.String() + "'"
+}
 
-	if !found {
-		if path == "."+key {
-			return nil, "", nil
-		}
+// PathGetValue gets the value at the path, see ParsePath for the syntax of the path
+func PathGetValue(value interface{}, path string) (interface{}, error) {
+
+	if path == "" {
+		return value, nil
+	}
 
-		return nil, "", errors.New("Invalid path '" + path + "'. path not found.")
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return val, path[npIdx:], nil
+	return p.Get(value)
 }
 
-func pathGetSetMapParamsValue(params map[string]string, path string, value interface{}, set bool) (interface{}, string, error) {
+// PathSetValue sets the value at the path in place, nested objects and arrays are created as needed.
+// An error is returned if the value at the root has to be created or replaced, ex. a nil value or
+// extending a root array, use PathUpdateValue for that.
+func PathSetValue(attrValue interface{}, path string, value interface{}) error {
 
-	key, _ := getMapKey(path[2:])
-	if set && key + `"]` == path[2:] {
-		//end of path so set the value
-		paramVal, err := CoerceToString(value)
+	if path == "" {
+		return nil
+	}
 
-		if err != nil {
-			return nil, "", err
+	p, err := ParsePath(path)
+	if err != nil {
+		return err
+	}
+
+	if attrValue == nil {
+		return fmt.Errorf("unable to set %s: value is nil", path)
+	}
+
+	if arr, ok := toArray(attrValue); ok && !p[0].isKey() {
+		if idx, ok := resolveIndex(p[0].Index, len(arr)); p[0].Append || (p[0].IsIndex && (!ok || idx >= len(arr))) {
+			return fmt.Errorf("unable to set %s: index out of range", path)
 		}
-		params[key] = paramVal
-		return nil, "", nil
 	}
 
-	val, found := params[key]
+	if reflect.TypeOf(attrValue).Kind() != reflect.Map && reflect.TypeOf(attrValue).Kind() != reflect.Slice {
+		return fmt.Errorf("unable to set %s: %T is not an object or array", path, attrValue)
+	}
+
+	newRoot, err := p.Set(attrValue, value)
+	if err != nil {
+		return err
+	}
+
+	if !sameValue(attrValue, newRoot) {
+		return fmt.Errorf("unable to set %s: the value has to be replaced", path)
+	}
+
+	return nil
+}
+
+// PathUpdateValue sets the value at the path and returns the updated root, which is created if nil
+func PathUpdateValue(root interface{}, path string, value interface{}) (interface{}, error) {
+
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Set(root, value)
+}
+
+// PathDeleteValue deletes the value at the path and returns the updated root
+func PathDeleteValue(root interface{}, path string) (interface{}, error) {
 
-	if !found {
-		return nil, "", errors.New("Invalid path '" + path + "'. path not found.")
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return val, "", nil
+	return p.Delete(root)
 }
 
 func PathDeconstruct(fullPath string) (attrName string, path string, err error) {
@@ -157,3 +157,160 @@ func TestPathSetValue(t *testing.T) {
 	//assert.Nil(t, err)
 	//////todo check if map
 }
+
+func TestParsePath(t *testing.T) {
+
+	p, err := ParsePath(`items[3].tags[0]`)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(p))
+	assert.Equal(t, "items", p[0].Key)
+	assert.True(t, p[1].IsIndex)
+	assert.Equal(t, 3, p[1].Index)
+	assert.Equal(t, ".items[3].tags[0]", p.String())
+
+	p, err = ParsePath(`["a.b"]['c'][-1][][*]`)
+	assert.Nil(t, err)
+	assert.Equal(t, "a.b", p[0].Key)
+	assert.Equal(t, "c", p[1].Key)
+	assert.Equal(t, -1, p[2].Index)
+	assert.True(t, p[3].Append)
+	assert.True(t, p[4].Wildcard)
+
+	_, err = ParsePath(`.a[x]`)
+	assert.NotNil(t, err)
+	_, err = ParsePath(`.a[0`)
+	assert.NotNil(t, err)
+	_, err = ParsePath(`.a..b`)
+	assert.NotNil(t, err)
+}
+
+func TestPathUpdateValue(t *testing.T) {
+
+	root, err := PathUpdateValue(nil, "items[3].tags[0]", "a")
+	assert.Nil(t, err)
+	items := root.(map[string]interface{})["items"].([]interface{})
+	assert.Equal(t, 4, len(items))
+	assert.Nil(t, items[0])
+	assert.Equal(t, []interface{}{"a"}, items[3].(map[string]interface{})["tags"])
+
+	root, err = PathUpdateValue(root, "items[3].tags[]", "b")
+	assert.Nil(t, err)
+	root, err = PathUpdateValue(root, "items[-1].tags[-1]", "c")
+	assert.Nil(t, err)
+
+	val, err := PathGetValue(root, "items[3].tags")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"a", "c"}, val)
+
+	_, err = PathUpdateValue(root, "items[-5].id", 1)
+	assert.NotNil(t, err)
+
+	arr, err := PathUpdateValue([]interface{}{}, "[]", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1}, arr)
+
+	_, err = PathUpdateValue(map[string]interface{}{"a": 1}, "a.b", 1)
+	assert.NotNil(t, err)
+
+	// only missing values are created, an existing empty object isn't replaced by an array
+	_, err = PathUpdateValue(map[string]interface{}{"a": map[string]interface{}{}}, "a[0]", 1)
+	assert.NotNil(t, err)
+
+	err = PathSetValue(map[string]interface{}{}, "[0]", 1)
+	assert.NotNil(t, err)
+}
+
+func TestPathSetValueRoot(t *testing.T) {
+
+	// a root that has to be created or replaced can't be set in place
+	err := PathSetValue(nil, "a", 1)
+	assert.NotNil(t, err)
+
+	err = PathSetValue("str", "a", 1)
+	assert.NotNil(t, err)
+
+	err = PathSetValue([]map[string]interface{}{{"id": 1}}, "[1].id", 2)
+	assert.NotNil(t, err)
+
+	root, err := PathUpdateValue(nil, "a", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 1}, root)
+}
+
+func TestPathSetValueKeepsArrayType(t *testing.T) {
+
+	items := []map[string]interface{}{{"id": 1}, {"id": 2}}
+	root := map[string]interface{}{"items": items}
+
+	err := PathSetValue(root, "items[1].status", "done")
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]interface{}{{"id": 1}, {"id": 2, "status": "done"}}, root["items"])
+
+	err = PathSetValue(root, "items[0]", map[string]interface{}{"id": 3})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"id": 3}, items[0])
+
+	newRoot, err := PathUpdateValue(root, "items[]", map[string]interface{}{"id": 4})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(newRoot.(map[string]interface{})["items"].([]map[string]interface{})))
+
+	// an element that isn't an object can't be stored
+	err = PathSetValue(root, "items[0]", 1)
+	assert.NotNil(t, err)
+
+	arr := []map[string]interface{}{{"id": 1}}
+	err = PathSetValue(arr, "[0]", map[string]interface{}{"id": 5})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"id": 5}, arr[0])
+}
+
+func TestPathWildcard(t *testing.T) {
+
+	root, _ := CoerceToObject(`{"items":[{"id":1},{"id":2}]}`)
+
+	val, err := PathGetValue(root, "items[*].id")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1.0, 2.0}, val)
+
+	err = PathSetValue(root, "items[*].status", "done")
+	assert.Nil(t, err)
+
+	val, err = PathGetValue(root, "items[*].status")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"done", "done"}, val)
+}
+
+func TestPathDeleteValue(t *testing.T) {
+
+	root, _ := CoerceToObject(`{"a":{"b":1,"c":2},"items":[1,2,3]}`)
+
+	newRoot, err := PathDeleteValue(root, "a.b")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"c": 2.0}, newRoot.(map[string]interface{})["a"])
+
+	newRoot, err = PathDeleteValue(newRoot, "items[-1]")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1.0, 2.0}, newRoot.(map[string]interface{})["items"])
+
+	newRoot, err = PathDeleteValue(newRoot, "missing.key")
+	assert.Nil(t, err)
+
+	arr, err := PathDeleteValue([]interface{}{1, 2, 3}, "[0]")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{2, 3}, arr)
+}
+
+func TestPathGetValueNegativeIndex(t *testing.T) {
+
+	arrVal, _ := CoerceToArray("[1,6,3]")
+
+	val, err := PathGetValue(arrVal, "[-1]")
+	assert.Nil(t, err)
+	assert.Equal(t, 3.0, val)
+
+	_, err = PathGetValue(arrVal, "[-4]")
+	assert.NotNil(t, err)
+
+	err = PathSetValue(arrVal, "[3]", 1)
+	assert.NotNil(t, err)
+}
@@ -1,9 +1,19 @@
 package data
 
-var propertyProvider PropertyProvider
+import (
+	"sync"
+)
+
+var (
+	providersMu      sync.RWMutex
+	propertyProvider PropertyProvider
+)
+
+var secretProvider SecretProvider
 
 func init() {
 	propertyProvider = &DefaultPropertyProvider{}
+	secretProvider = &DefaultSecretProvider{}
 }
 
 type PropertyProvider interface {
@@ -11,10 +21,16 @@ type PropertyProvider interface {
 }
 
 func SetPropertyProvider(provider PropertyProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
 	propertyProvider = provider
 }
 
 func GetPropertyProvider() PropertyProvider {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
 	return propertyProvider
 }
 
@@ -25,3 +41,29 @@ type DefaultPropertyProvider struct {
 func (pp *DefaultPropertyProvider) GetProperty(property string) (value interface{}, exists bool) {
 	return nil, false
 }
+
+type SecretProvider interface {
+	GetSecret(name string) (value string, exists bool, err error)
+}
+
+func SetSecretProvider(provider SecretProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	secretProvider = provider
+}
+
+func GetSecretProvider() SecretProvider {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	return secretProvider
+}
+
+// DefaultSecretProvider empty secret provider
+type DefaultSecretProvider struct {
+}
+
+func (sp *DefaultSecretProvider) GetSecret(name string) (value string, exists bool, err error) {
+	return "", false, nil
+}
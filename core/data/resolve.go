@@ -2,7 +2,6 @@ package data
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/TIBCOSoftware/flogo-lib/logger"
@@ -47,31 +46,12 @@ func (r *BasicResolver) Resolve(toResolve string, scope Scope) (value interface{
 		return nil, fmt.Errorf("unable to resolve '%s'", toResolve)
 	}
 
-	var exists bool
-
-	switch details.ResolverName {
-	case "property":
-		// Property resolution
-		provider := GetPropertyProvider()
-		value, exists = provider.GetProperty(details.Property + details.Path) //should we add the path and reset it to ""
-		if !exists {
-			err := fmt.Errorf("failed to resolve Property: '%s', ensure that property is configured in the application", details.Property)
-			logger.Error(err.Error())
-			return nil, err
-		}
-	case "env":
-		// Environment resolution
-		value, exists = os.LookupEnv(details.Property + details.Path)
-		if !exists {
-			err := fmt.Errorf("failed to resolve Environment Variable: '%s', ensure that variable is configured", details.Property)
-			logger.Error(err.Error())
-			return "", err
-		}
-	default:
+	resolverFunc, exists := GetResolver(details.ResolverName)
+	if !exists {
 		return nil, fmt.Errorf("unsupported resolver: %s", details.ResolverName)
 	}
 
-	return value, nil
+	return resolverFunc(details, scope)
 }
 
 func SimpleScopeResolve(toResolve string, scope Scope) (value interface{}, err error) {
@@ -111,18 +91,38 @@ func GetResolutionDetails(toResolve string) (*ResolutionDetails, error) {
 
 	//todo optimize, maybe tokenize first
 
-	dotIdx := strings.Index(toResolve, ".")
+	sepIdx := strings.IndexFunc(toResolve, isSep)
 
-	if dotIdx == -1 {
+	if sepIdx == -1 {
 		return nil, fmt.Errorf("invalid resolution expression [%s]", toResolve)
 	}
 
 	details := &ResolutionDetails{}
-	itemIdx := strings.Index(toResolve[:dotIdx], "[")
+	dotIdx := sepIdx
+
+	if toResolve[sepIdx] == '[' {
+		closeIdx := strings.Index(toResolve[sepIdx:], "]")
+
+		if closeIdx == -1 {
+			return nil, fmt.Errorf("invalid resolution expression [%s]", toResolve)
+		}
+		closeIdx += sepIdx
+
+		details.ResolverName = toResolve[:sepIdx]
+		details.Item = toResolve[sepIdx+1 : closeIdx]
+
+		rest := toResolve[closeIdx+1:]
+
+		if rest == "" {
+			// ex. file[path]
+			return details, nil
+		} else if rest[0] != '.' {
+			// ex. global[item][0]
+			details.Path = rest
+			return details, nil
+		}
 
-	if itemIdx != -1 {
-		details.Item = toResolve[itemIdx+1 : dotIdx-1]
-		details.ResolverName = toResolve[:itemIdx]
+		dotIdx = closeIdx + 1
 	} else {
 		details.ResolverName = toResolve[:dotIdx]
 
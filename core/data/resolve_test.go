@@ -1,8 +1,14 @@
 package data
 
 import (
-	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGetResolutionDetails(t *testing.T) {
@@ -165,4 +171,129 @@ func TestGetResolutionDetailsOld(t *testing.T) {
 	assert.Equal(t, "myArrayAttributeName", details.Property)
 	assert.Equal(t, "myactivityId", details.Item)
 	assert.Equal(t, "[0]", details.Path)
-}
\ No newline at end of file
+}
+
+func TestGetResolutionDetailsItemOnly(t *testing.T) {
+
+	details, err := GetResolutionDetails("file[/tmp/data.json]")
+	assert.Nil(t, err)
+	assert.Equal(t, "file", details.ResolverName)
+	assert.Equal(t, "/tmp/data.json", details.Item)
+	assert.Equal(t, "", details.Property)
+	assert.Equal(t, "", details.Path)
+
+	details, err = GetResolutionDetails("global[counters][0]")
+	assert.Nil(t, err)
+	assert.Equal(t, "global", details.ResolverName)
+	assert.Equal(t, "counters", details.Item)
+	assert.Equal(t, "[0]", details.Path)
+
+	details, err = GetResolutionDetails("secret[db].user")
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", details.ResolverName)
+	assert.Equal(t, "db", details.Item)
+	assert.Equal(t, "user", details.Property)
+
+	name, path := details.NameAndPath()
+	assert.Equal(t, "db", name)
+	assert.Equal(t, ".user", path)
+
+	_, err = GetResolutionDetails("secret[db")
+	assert.NotNil(t, err)
+}
+
+type testSecretProvider map[string]string
+
+func (sp testSecretProvider) GetSecret(name string) (string, bool, error) {
+	value, exists := sp[name]
+	return value, exists, nil
+}
+
+func TestBasicResolverResolvers(t *testing.T) {
+
+	resolver := GetBasicResolver()
+
+	// global
+	GetGlobalScope().AddAttr("config", TypeObject, map[string]interface{}{"retries": 3})
+	value, err := resolver.Resolve("$global[config].retries", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, value)
+	value, err = resolver.Resolve("$global.config.retries", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, value)
+
+	// secret
+	origSecretProvider := GetSecretProvider()
+	SetSecretProvider(testSecretProvider{"token": "abc", "db": `{"user":"admin"}`})
+	defer SetSecretProvider(origSecretProvider)
+
+	value, err = resolver.Resolve("$secret[token]", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", value)
+	value, err = resolver.Resolve("$secret[db].user", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", value)
+	_, err = resolver.Resolve("$secret[missing]", nil)
+	assert.NotNil(t, err)
+
+	// trigger
+	attr, _ := NewAttribute("_T.payload", TypeObject, map[string]interface{}{"id": "1"})
+	scope := NewSimpleScope([]*Attribute{attr}, nil)
+	value, err = resolver.Resolve("$trigger[payload].id", scope)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", value)
+
+	// file
+	f, err := ioutil.TempFile("", "resolve")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	f.WriteString(`{"items":["a","b"]}`)
+	f.Close()
+
+	value, err = resolver.Resolve("$file["+f.Name()+"]", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"items":["a","b"]}`, value)
+	value, err = resolver.Resolve("$file["+f.Name()+"].items[1]", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "b", value)
+
+	// custom
+	RegisterResolver("upper", func(details *ResolutionDetails, scope Scope) (interface{}, error) {
+		name, _ := details.NameAndPath()
+		return strings.ToUpper(name), nil
+	})
+	value, err = resolver.Resolve("$upper[abc]", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "ABC", value)
+
+	_, err = resolver.Resolve("$unknown[abc]", nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "unsupported resolver: unknown", err.Error())
+}
+
+func TestRegisterResolverConcurrent(t *testing.T) {
+
+	resolve := func(details *ResolutionDetails, scope Scope) (interface{}, error) { return nil, nil }
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			RegisterResolver("concurrent"+strconv.Itoa(i), resolve)
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			_, exists := GetResolver("property")
+			assert.True(t, exists)
+		}()
+	}
+
+	wg.Wait()
+
+	_, exists := GetResolver("concurrent9")
+	assert.True(t, exists)
+}
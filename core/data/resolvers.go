@@ -0,0 +1,206 @@
+package data
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/TIBCOSoftware/flogo-lib/logger"
+	"github.com/TIBCOSoftware/flogo-lib/util"
+)
+
+// ResolverFunc resolves the value for the specified resolution details
+type ResolverFunc func(details *ResolutionDetails, scope Scope) (value interface{}, err error)
+
+var (
+	resolversMu sync.RWMutex
+	resolvers   = make(map[string]ResolverFunc)
+)
+
+func init() {
+	RegisterResolver("property", resolveProperty)
+	RegisterResolver("env", resolveEnv)
+	RegisterResolver("file", resolveFile)
+	RegisterResolver("global", resolveGlobal)
+	RegisterResolver("secret", resolveSecret)
+	RegisterResolver("trigger", resolveTrigger)
+}
+
+// RegisterResolver registers the resolver for the specified name, ex. "property" for "$property[name]",
+// registering a resolver for an existing name replaces it
+func RegisterResolver(name string, resolverFunc ResolverFunc) error {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+
+	if len(name) == 0 {
+		return fmt.Errorf("'name' must be specified when registering a resolver")
+	}
+
+	if resolverFunc == nil {
+		return fmt.Errorf("cannot register 'nil' resolver")
+	}
+
+	resolvers[name] = resolverFunc
+
+	return nil
+}
+
+// GetResolver gets the resolver registered for the specified name
+func GetResolver(name string) (ResolverFunc, bool) {
+	resolversMu.RLock()
+	defer resolversMu.RUnlock()
+
+	resolverFunc, exists := resolvers[name]
+	return resolverFunc, exists
+}
+
+// NameAndPath returns the name of the item to resolve and the path within its value,
+// supporting both the "$name[item].path" and "$name.item.path" forms
+func (d *ResolutionDetails) NameAndPath() (string, string) {
+
+	if d.Item != "" {
+		if d.Property != "" {
+			return d.Item, "." + d.Property + d.Path
+		}
+		return d.Item, d.Path
+	}
+
+	return d.Property, d.Path
+}
+
+func resolveProperty(details *ResolutionDetails, scope Scope) (interface{}, error) {
+
+	name, path := details.NameAndPath()
+	provider := GetPropertyProvider()
+
+	// property names can contain dots, so first try the full name
+	if details.Item == "" && path != "" {
+		if value, exists := provider.GetProperty(name + path); exists {
+			return value, nil
+		}
+	}
+
+	value, exists := provider.GetProperty(name)
+	if !exists {
+		err := fmt.Errorf("failed to resolve Property: '%s', ensure that property is configured in the application", name)
+		logger.Error(err.Error())
+		return nil, err
+	}
+
+	return PathGetValue(value, path)
+}
+
+func resolveEnv(details *ResolutionDetails, scope Scope) (interface{}, error) {
+
+	name, path := details.NameAndPath()
+
+	// variable names can contain dots, so first try the full name
+	if details.Item == "" && path != "" {
+		if value, exists := os.LookupEnv(name + path); exists {
+			return value, nil
+		}
+	}
+
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		err := fmt.Errorf("failed to resolve Environment Variable: '%s', ensure that variable is configured", name)
+		logger.Error(err.Error())
+		return "", err
+	}
+
+	return PathGetValue(value, path)
+}
+
+func resolveFile(details *ResolutionDetails, scope Scope) (interface{}, error) {
+
+	if details.Item == "" {
+		return nil, fmt.Errorf("file path not specified, expected $file[path]")
+	}
+
+	fileName := details.Item
+	if filePath, isURL := util.URLStringToFilePath(fileName); isURL {
+		fileName = filePath
+	}
+
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve File: '%s', %s", details.Item, err.Error())
+	}
+
+	value := string(content)
+
+	path := details.Path
+	if details.Property != "" {
+		path = "." + details.Property + details.Path
+	}
+
+	if path == "" {
+		return value, nil
+	}
+
+	// navigating into the file requires it to be JSON
+	var obj interface{}
+	if err := decodeJSON(content, &obj); err != nil {
+		return nil, fmt.Errorf("failed to resolve File: '%s', %s", details.Item, err.Error())
+	}
+
+	return PathGetValue(obj, path)
+}
+
+func resolveGlobal(details *ResolutionDetails, scope Scope) (interface{}, error) {
+
+	name, path := details.NameAndPath()
+
+	attr, exists := GetGlobalScope().GetAttr(name)
+	if !exists {
+		return nil, fmt.Errorf("failed to resolve Global: '%s', ensure that it is set in the global scope", name)
+	}
+
+	return PathGetValue(attr.Value(), path)
+}
+
+func resolveSecret(details *ResolutionDetails, scope Scope) (interface{}, error) {
+
+	name, path := details.NameAndPath()
+
+	value, exists, err := GetSecretProvider().GetSecret(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve Secret: '%s', %s", name, err.Error())
+	}
+	if !exists {
+		return nil, fmt.Errorf("failed to resolve Secret: '%s', ensure that secret is configured", name)
+	}
+
+	if path == "" {
+		return value, nil
+	}
+
+	// navigating into the secret requires it to be JSON
+	var obj interface{}
+	if err := decodeJSON([]byte(value), &obj); err != nil {
+		return nil, fmt.Errorf("failed to resolve Secret: '%s', %s", name, err.Error())
+	}
+
+	return PathGetValue(obj, path)
+}
+
+func resolveTrigger(details *ResolutionDetails, scope Scope) (interface{}, error) {
+
+	name, path := details.NameAndPath()
+
+	if scope == nil {
+		return nil, fmt.Errorf("failed to resolve Trigger: '%s', no trigger data available", name)
+	}
+
+	attr, exists := scope.GetAttr(name)
+	if !exists {
+		// trigger data passed directly to the action is prefixed
+		attr, exists = scope.GetAttr("_T." + name)
+	}
+	if !exists {
+		return nil, fmt.Errorf("failed to resolve Trigger: '%s'", name)
+	}
+
+	return PathGetValue(attr.Value(), path)
+}
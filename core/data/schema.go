@@ -0,0 +1,697 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var schemaValidation int32
+
+// SetSchemaValidation enables or disables the validation of complex object values against their JSON Schema
+func SetSchemaValidation(enabled bool) {
+	var flag int32
+	if enabled {
+		flag = 1
+	}
+	atomic.StoreInt32(&schemaValidation, flag)
+}
+
+// SchemaValidationEnabled indicates if complex object values are validated against their JSON Schema
+func SchemaValidationEnabled() bool {
+	return atomic.LoadInt32(&schemaValidation) == 1
+}
+
+// parsedSchema is a JSON Schema parsed from the metadata of a complex object
+type parsedSchema struct {
+	root map[string]interface{}
+}
+
+var (
+	schemaCacheMutex sync.RWMutex
+	schemaCache      = make(map[string]*parsedSchema)
+)
+
+// SchemaViolation is a violation of a JSON Schema, the path points at the failing value, ex. "$.items[0].id"
+type SchemaViolation struct {
+	Path    string
+	Message string
+}
+
+func (v *SchemaViolation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// SchemaValidationError is the error returned when a value doesn't conform to its JSON Schema
+type SchemaValidationError struct {
+	Violations []*SchemaViolation
+}
+
+func (e *SchemaValidationError) Error() string {
+
+	msgs := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		msgs[i] = violation.String()
+	}
+
+	return "value does not conform to schema: " + strings.Join(msgs, "; ")
+}
+
+// Validate validates the value of the complex object against the JSON Schema in its metadata,
+// a complex object without metadata is always valid
+func (co *ComplexObject) Validate() error {
+
+	if co == nil || strings.TrimSpace(co.Metadata) == "" {
+		return nil
+	}
+
+	schema, err := getParsedSchema(co.Metadata)
+	if err != nil {
+		return err
+	}
+
+	return schema.validate(co.Value)
+}
+
+// getParsedSchema gets the parsed JSON Schema, schemas are parsed once and cached by their source
+func getParsedSchema(schema string) (*parsedSchema, error) {
+
+	schemaCacheMutex.RLock()
+	cached, exists := schemaCache[schema]
+	schemaCacheMutex.RUnlock()
+
+	if exists {
+		return cached, nil
+	}
+
+	parsed, err := parseSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaCacheMutex.Lock()
+	schemaCache[schema] = parsed
+	schemaCacheMutex.Unlock()
+
+	return parsed, nil
+}
+
+// ValidateSchema validates the value against the JSON Schema, a *SchemaValidationError is returned
+// if the value doesn't conform to the schema. The value can either be a JSON string or a value that
+// can be marshalled to JSON. An error is returned if the schema uses a keyword that isn't supported.
+func ValidateSchema(schema string, value interface{}) error {
+
+	parsed, err := getParsedSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	return parsed.validate(value)
+}
+
+func parseSchema(schema string) (*parsedSchema, error) {
+
+	var root map[string]interface{}
+
+	decoder := json.NewDecoder(strings.NewReader(schema))
+	decoder.UseNumber()
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema: %s", err.Error())
+	}
+
+	parsed := &parsedSchema{root: root}
+	if err := parsed.check(root, "#"); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema: %s", err.Error())
+	}
+
+	return parsed, nil
+}
+
+func (s *parsedSchema) validate(value interface{}) error {
+
+	normalized, err := normalizeJSON(value)
+	if err != nil {
+		return err
+	}
+
+	v := &schemaValidator{schema: s, activeRefs: make(map[string]bool)}
+	v.validateValue(s.root, normalized, "$")
+
+	if len(v.violations) > 0 {
+		return &SchemaValidationError{Violations: v.violations}
+	}
+
+	return nil
+}
+
+// normalizeJSON converts the value to its generic JSON representation, numbers are kept as json.Number
+func normalizeJSON(value interface{}) (interface{}, error) {
+
+	var b []byte
+
+	if strVal, ok := value.(string); ok {
+		b = []byte(strVal)
+	} else {
+		var err error
+		b, err = json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var normalized interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, fmt.Errorf("value is not valid JSON: %s", err.Error())
+	}
+
+	return normalized, nil
+}
+
+// schemaAnnotations are the keywords that don't affect validation
+var schemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "readOnly": true, "writeOnly": true, "deprecated": true,
+}
+
+// schemaKeywords are the validation keywords that take a plain value
+var schemaKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true, "required": true,
+	"minLength": true, "maxLength": true, "minimum": true, "maximum": true,
+	"exclusiveMinimum": true, "exclusiveMaximum": true, "multipleOf": true,
+	"minItems": true, "maxItems": true, "uniqueItems": true, "minProperties": true, "maxProperties": true,
+}
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// schemaFormats are the supported values of the "format" keyword
+var schemaFormats = map[string]func(string) bool{
+	"date-time": func(s string) bool { _, err := time.Parse(time.RFC3339, s); return err == nil },
+	"date":      func(s string) bool { _, err := time.Parse("2006-01-02", s); return err == nil },
+	"time":      func(s string) bool { _, err := time.Parse("15:04:05Z07:00", s); return err == nil },
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	},
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool { return net.ParseIP(s) != nil && strings.Contains(s, ":") },
+	"uuid": uuidRegex.MatchString,
+}
+
+// check verifies that the schema only uses supported keywords, a schema that uses a keyword
+// that isn't supported is rejected instead of silently accepting every value
+func (s *parsedSchema) check(schema interface{}, path string) error {
+
+	if _, ok := schema.(bool); ok {
+		return nil
+	}
+
+	schemaDef, ok := schema.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("schema at '%s' must be an object", path)
+	}
+
+	var keywords []string
+	for keyword := range schemaDef {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	for _, keyword := range keywords {
+
+		value := schemaDef[keyword]
+		keywordPath := path + "/" + keyword
+
+		switch keyword {
+		case "definitions", "$defs", "properties", "patternProperties":
+			subSchemas, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("'%s' must be an object", keywordPath)
+			}
+			for name, subSchema := range subSchemas {
+				if keyword == "patternProperties" {
+					if _, err := regexp.Compile(name); err != nil {
+						return fmt.Errorf("invalid pattern '%s' at '%s'", name, keywordPath)
+					}
+				}
+				if err := s.check(subSchema, keywordPath+"/"+name); err != nil {
+					return err
+				}
+			}
+		case "allOf", "anyOf", "oneOf":
+			subSchemas, ok := value.([]interface{})
+			if !ok {
+				return fmt.Errorf("'%s' must be an array", keywordPath)
+			}
+			for i, subSchema := range subSchemas {
+				if err := s.check(subSchema, keywordPath+"/"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+		case "not", "additionalProperties", "items":
+			if err := s.check(value, keywordPath); err != nil {
+				return err
+			}
+		case "$ref":
+			ref, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("'%s' must be a string", keywordPath)
+			}
+			if _, err := s.resolveRef(ref); err != nil {
+				return err
+			}
+		case "pattern":
+			pattern, _ := value.(string)
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid pattern '%v' at '%s'", value, keywordPath)
+			}
+		case "format":
+			format, _ := value.(string)
+			if _, exists := schemaFormats[format]; !exists {
+				return fmt.Errorf("unsupported format '%v' at '%s'", value, keywordPath)
+			}
+		default:
+			if !schemaAnnotations[keyword] && !schemaKeywords[keyword] {
+				return fmt.Errorf("unsupported keyword '%s' at '%s'", keyword, path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveRef resolves a reference to a schema within the same document, ex. "#/definitions/address"
+func (s *parsedSchema) resolveRef(ref string) (interface{}, error) {
+
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref '%s', only references within the schema are supported", ref)
+	}
+
+	var current interface{} = s.root
+
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return current, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid $ref '%s'", ref)
+	}
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+
+		switch t := current.(type) {
+		case map[string]interface{}:
+			var exists bool
+			if current, exists = t[token]; !exists {
+				return nil, fmt.Errorf("unresolved $ref '%s'", ref)
+			}
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(t) {
+				return nil, fmt.Errorf("unresolved $ref '%s'", ref)
+			}
+			current = t[idx]
+		default:
+			return nil, fmt.Errorf("unresolved $ref '%s'", ref)
+		}
+	}
+
+	return current, nil
+}
+
+// schemaValidator collects the violations of a value against a parsed schema
+type schemaValidator struct {
+	schema     *parsedSchema
+	violations []*SchemaViolation
+
+	// activeRefs are the references being followed, keyed by reference and path, used to
+	// stop recursive schemas that reference themselves without descending into the value
+	activeRefs map[string]bool
+}
+
+func (v *schemaValidator) addViolation(path string, format string, args ...interface{}) {
+	v.violations = append(v.violations, &SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches indicates if the value conforms to the schema, without collecting violations
+func (v *schemaValidator) matches(schema interface{}, value interface{}, path string) bool {
+
+	sub := &schemaValidator{schema: v.schema, activeRefs: v.activeRefs}
+	sub.validateValue(schema, value, path)
+
+	return len(sub.violations) == 0
+}
+
+func (v *schemaValidator) validateValue(schema interface{}, value interface{}, path string) {
+
+	if allowed, ok := schema.(bool); ok {
+		if !allowed {
+			v.addViolation(path, "value not allowed")
+		}
+		return
+	}
+
+	schemaDef, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if ref, ok := schemaDef["$ref"].(string); ok {
+		key := ref + " " + path
+		if !v.activeRefs[key] {
+			v.activeRefs[key] = true
+			target, _ := v.schema.resolveRef(ref)
+			v.validateValue(target, value, path)
+			delete(v.activeRefs, key)
+		}
+	}
+
+	if schemaType, exists := schemaDef["type"]; exists {
+		if !matchesSchemaType(schemaType, value) {
+			v.addViolation(path, "expected %v but was %s", schemaType, jsonTypeOf(value))
+			return
+		}
+	}
+
+	if enum, ok := schemaDef["enum"].([]interface{}); ok {
+		matched := false
+		for _, enumVal := range enum {
+			if jsonEqual(enumVal, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.addViolation(path, "value must be one of %v", enum)
+		}
+	}
+
+	if constVal, exists := schemaDef["const"]; exists && !jsonEqual(constVal, value) {
+		v.addViolation(path, "value must be %v", constVal)
+	}
+
+	if subSchemas, ok := schemaDef["allOf"].([]interface{}); ok {
+		for _, subSchema := range subSchemas {
+			v.validateValue(subSchema, value, path)
+		}
+	}
+
+	if subSchemas, ok := schemaDef["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, subSchema := range subSchemas {
+			if v.matches(subSchema, value, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.addViolation(path, "value does not match any of the schemas")
+		}
+	}
+
+	if subSchemas, ok := schemaDef["oneOf"].([]interface{}); ok {
+		matched := 0
+		for _, subSchema := range subSchemas {
+			if v.matches(subSchema, value, path) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.addViolation(path, "value must match exactly one of the schemas, matched %d", matched)
+		}
+	}
+
+	if notSchema, exists := schemaDef["not"]; exists && v.matches(notSchema, value, path) {
+		v.addViolation(path, "value must not match the schema")
+	}
+
+	switch t := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(schemaDef, t, path)
+	case []interface{}:
+		v.validateArray(schemaDef, t, path)
+	case string:
+		length := float64(len([]rune(t)))
+		if min, ok := schemaNumber(schemaDef, "minLength"); ok && length < min {
+			v.addViolation(path, "length must be at least %v", min)
+		}
+		if max, ok := schemaNumber(schemaDef, "maxLength"); ok && length > max {
+			v.addViolation(path, "length must be at most %v", max)
+		}
+		if pattern, ok := schemaDef["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(t) {
+				v.addViolation(path, "value must match the pattern '%s'", pattern)
+			}
+		}
+		if format, ok := schemaDef["format"].(string); ok {
+			if isFormat, exists := schemaFormats[format]; exists && !isFormat(t) {
+				v.addViolation(path, "value must be a valid %s", format)
+			}
+		}
+	case json.Number:
+		v.validateNumber(schemaDef, t, path)
+	}
+}
+
+func (v *schemaValidator) validateNumber(schema map[string]interface{}, value json.Number, path string) {
+
+	num, _ := value.Float64()
+
+	// draft-04 uses boolean exclusiveMinimum/exclusiveMaximum that apply to minimum/maximum
+	exclusiveMin, _ := schema["exclusiveMinimum"].(bool)
+	exclusiveMax, _ := schema["exclusiveMaximum"].(bool)
+
+	if min, ok := schemaNumber(schema, "minimum"); ok {
+		if exclusiveMin && num <= min {
+			v.addViolation(path, "value must be greater than %v", min)
+		} else if num < min {
+			v.addViolation(path, "value must be at least %v", min)
+		}
+	}
+	if max, ok := schemaNumber(schema, "maximum"); ok {
+		if exclusiveMax && num >= max {
+			v.addViolation(path, "value must be less than %v", max)
+		} else if num > max {
+			v.addViolation(path, "value must be at most %v", max)
+		}
+	}
+	if min, ok := schemaNumber(schema, "exclusiveMinimum"); ok && num <= min {
+		v.addViolation(path, "value must be greater than %v", min)
+	}
+	if max, ok := schemaNumber(schema, "exclusiveMaximum"); ok && num >= max {
+		v.addViolation(path, "value must be less than %v", max)
+	}
+
+	if multipleOf, ok := schema["multipleOf"].(json.Number); ok {
+		divisor, divisorOk := new(big.Rat).SetString(multipleOf.String())
+		dividend, dividendOk := new(big.Rat).SetString(value.String())
+		if divisorOk && dividendOk && divisor.Sign() != 0 && !new(big.Rat).Quo(dividend, divisor).IsInt() {
+			v.addViolation(path, "value must be a multiple of %v", multipleOf)
+		}
+	}
+}
+
+func (v *schemaValidator) validateObject(schema map[string]interface{}, obj map[string]interface{}, path string) {
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if strName, ok := name.(string); ok {
+				if _, exists := obj[strName]; !exists {
+					v.addViolation(path, "missing required property '%s'", strName)
+				}
+			}
+		}
+	}
+
+	count := float64(len(obj))
+	if min, ok := schemaNumber(schema, "minProperties"); ok && count < min {
+		v.addViolation(path, "must have at least %v properties", min)
+	}
+	if max, ok := schemaNumber(schema, "maxProperties"); ok && count > max {
+		v.addViolation(path, "must have at most %v properties", max)
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	patternProperties, _ := schema["patternProperties"].(map[string]interface{})
+
+	var names []string
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+
+		propPath := path + "." + name
+		matched := false
+
+		if propSchema, exists := properties[name]; exists {
+			v.validateValue(propSchema, obj[name], propPath)
+			matched = true
+		}
+
+		for pattern, propSchema := range patternProperties {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(name) {
+				v.validateValue(propSchema, obj[name], propPath)
+				matched = true
+			}
+		}
+
+		if additional, exists := schema["additionalProperties"]; exists && !matched {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				v.addViolation(propPath, "additional property not allowed")
+			} else {
+				v.validateValue(additional, obj[name], propPath)
+			}
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(schema map[string]interface{}, arr []interface{}, path string) {
+
+	length := float64(len(arr))
+	if min, ok := schemaNumber(schema, "minItems"); ok && length < min {
+		v.addViolation(path, "must have at least %v items", min)
+	}
+	if max, ok := schemaNumber(schema, "maxItems"); ok && length > max {
+		v.addViolation(path, "must have at most %v items", max)
+	}
+
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := 1; i < len(arr); i++ {
+			for j := 0; j < i; j++ {
+				if jsonEqual(arr[j], arr[i]) {
+					v.addViolation(path, "items must be unique, item %d is a duplicate of item %d", i, j)
+				}
+			}
+		}
+	}
+
+	if items, exists := schema["items"]; exists {
+		for i, item := range arr {
+			v.validateValue(items, item, path+"["+strconv.Itoa(i)+"]")
+		}
+	}
+}
+
+func schemaNumber(schema map[string]interface{}, keyword string) (float64, bool) {
+
+	num, ok := schema[keyword].(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	f, err := num.Float64()
+	return f, err == nil
+}
+
+func matchesSchemaType(schemaType interface{}, value interface{}) bool {
+
+	switch t := schemaType.(type) {
+	case string:
+		return matchesJSONType(t, value)
+	case []interface{}:
+		for _, st := range t {
+			if strType, ok := st.(string); ok && matchesJSONType(strType, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func matchesJSONType(jsonType string, value interface{}) bool {
+
+	if jsonType == "integer" {
+		num, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		r, ok := new(big.Rat).SetString(num.String())
+		return ok && r.IsInt()
+	}
+
+	return jsonTypeOf(value) == jsonType
+}
+
+func jsonTypeOf(value interface{}) string {
+
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return "unknown"
+}
+
+// schemaNumberValue is the canonical form of a JSON number, used so that numbers that are
+// written differently but are equal, ex. 1 and 1.0, compare as equal
+type schemaNumberValue string
+
+func jsonEqual(schemaVal interface{}, value interface{}) bool {
+	return reflect.DeepEqual(normalizeNumbers(schemaVal), normalizeNumbers(value))
+}
+
+// normalizeNumbers replaces the numbers in the value with their canonical form, recursively
+func normalizeNumbers(value interface{}) interface{} {
+
+	switch t := value.(type) {
+	case json.Number:
+		if r, ok := new(big.Rat).SetString(t.String()); ok {
+			return schemaNumberValue(r.RatString())
+		}
+		return schemaNumberValue(t.String())
+	case float64:
+		return schemaNumberValue(new(big.Rat).SetFloat64(t).RatString())
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			normalized[key] = normalizeNumbers(val)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(t))
+		for i, val := range t {
+			normalized[i] = normalizeNumbers(val)
+		}
+		return normalized
+	}
+
+	return value
+}
@@ -0,0 +1,177 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const personSchema = `{
+  "type": "object",
+  "required": ["name"],
+  "additionalProperties": false,
+  "properties": {
+    "name": { "type": "string", "minLength": 1 },
+    "age": { "type": "integer", "minimum": 0 },
+    "address": {
+      "type": "object",
+      "properties": { "zip": { "type": "string", "pattern": "^[0-9]{5}$" } }
+    },
+    "tags": { "type": "array", "maxItems": 2, "items": { "type": "string", "enum": ["a", "b"] } }
+  }
+}`
+
+//TestValidateSchema
+func TestValidateSchema(t *testing.T) {
+
+	err := ValidateSchema(personSchema, map[string]interface{}{"name": "joe", "age": 30, "tags": []string{"a"}})
+	assert.Nil(t, err)
+
+	err = ValidateSchema(personSchema, `{"name": "joe", "address": {"zip": "123"}, "tags": ["a", "c"]}`)
+	assert.NotNil(t, err)
+
+	schemaErr, ok := err.(*SchemaValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(schemaErr.Violations))
+	assert.Equal(t, "$.address.zip", schemaErr.Violations[0].Path)
+	assert.Equal(t, "$.tags[1]", schemaErr.Violations[1].Path)
+
+	err = ValidateSchema(personSchema, map[string]interface{}{"age": 1.5, "other": true})
+	schemaErr = err.(*SchemaValidationError)
+	assert.Equal(t, 3, len(schemaErr.Violations))
+	assert.Equal(t, "$: missing required property 'name'", schemaErr.Violations[0].String())
+	assert.Equal(t, "$.age", schemaErr.Violations[1].Path)
+	assert.Equal(t, "$.other: additional property not allowed", schemaErr.Violations[2].String())
+
+	err = ValidateSchema(`{"type": "object"`, "{}")
+	assert.NotNil(t, err)
+}
+
+//TestCoerceToComplexObjectSchema
+func TestCoerceToComplexObjectSchema(t *testing.T) {
+
+	invalid := &ComplexObject{Metadata: personSchema, Value: map[string]interface{}{"age": 1}}
+
+	_, err := CoerceToComplexObject(invalid)
+	assert.Nil(t, err)
+
+	SetSchemaValidation(true)
+	defer SetSchemaValidation(false)
+
+	_, err = CoerceToComplexObject(invalid)
+	assert.NotNil(t, err)
+
+	co, err := CoerceToComplexObject(map[string]interface{}{"metadata": personSchema, "value": map[string]interface{}{"name": "joe"}})
+	assert.Nil(t, err)
+	assert.Equal(t, personSchema, co.Metadata)
+}
+
+//TestAttributeSchema
+func TestAttributeSchema(t *testing.T) {
+
+	SetSchemaValidation(true)
+	defer SetSchemaValidation(false)
+
+	attr, err := NewAttribute("person", TypeComplexObject, &ComplexObject{Metadata: personSchema, Value: map[string]interface{}{"name": "joe"}})
+	assert.Nil(t, err)
+
+	err = attr.SetValue(&ComplexObject{Value: map[string]interface{}{"name": "jane", "age": 20}})
+	assert.Nil(t, err)
+	assert.Equal(t, personSchema, attr.Value().(*ComplexObject).Metadata)
+
+	err = attr.SetValue(&ComplexObject{Value: map[string]interface{}{"name": ""}})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid 'person': value does not conform to schema: $.name")
+	assert.Equal(t, "jane", attr.Value().(*ComplexObject).Value.(map[string]interface{})["name"])
+}
+
+//TestComplexObjectSchemaCache
+func TestComplexObjectSchemaCache(t *testing.T) {
+
+	co := &ComplexObject{Metadata: personSchema, Value: map[string]interface{}{"name": "joe"}}
+	assert.Nil(t, co.Validate())
+
+	schemaCacheMutex.RLock()
+	_, cached := schemaCache[personSchema]
+	schemaCacheMutex.RUnlock()
+	assert.True(t, cached)
+
+	co.Metadata = `{"type": "object", "required": ["id"]}`
+	assert.NotNil(t, co.Validate())
+
+	// the cached schema isn't kept on the complex object
+	assert.Equal(t, &ComplexObject{Metadata: co.Metadata, Value: co.Value}, co)
+}
+
+const orderSchema = `{
+  "definitions": {
+    "item": {
+      "type": "object",
+      "required": ["sku"],
+      "properties": { "sku": { "type": "string" }, "parts": { "type": "array", "items": { "$ref": "#/definitions/item" } } }
+    }
+  },
+  "type": "object",
+  "properties": {
+    "items": { "type": "array", "items": { "$ref": "#/definitions/item" } }
+  }
+}`
+
+//TestValidateSchemaRef
+func TestValidateSchemaRef(t *testing.T) {
+
+	err := ValidateSchema(orderSchema, `{"items": [{"sku": "a", "parts": [{"sku": "b"}]}]}`)
+	assert.Nil(t, err)
+
+	err = ValidateSchema(orderSchema, `{"items": [{"sku": "a", "parts": [{"sku": 1}, {}]}]}`)
+	assert.NotNil(t, err)
+
+	schemaErr := err.(*SchemaValidationError)
+	assert.Equal(t, 2, len(schemaErr.Violations))
+	assert.Equal(t, "$.items[0].parts[0].sku: expected string but was number", schemaErr.Violations[0].String())
+	assert.Equal(t, "$.items[0].parts[1]: missing required property 'sku'", schemaErr.Violations[1].String())
+
+	err = ValidateSchema(`{"$ref": "#/definitions/missing"}`, `{}`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unresolved $ref '#/definitions/missing'")
+
+	err = ValidateSchema(`{"$ref": "http://example.com/schema.json"}`, `{}`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unsupported $ref")
+}
+
+//TestValidateSchemaKeywords
+func TestValidateSchemaKeywords(t *testing.T) {
+
+	schema := `{
+	  "oneOf": [{ "type": "string", "format": "email" }, { "type": "integer", "multipleOf": 5 }],
+	  "not": { "const": 10 }
+	}`
+
+	assert.Nil(t, ValidateSchema(schema, `"joe@example.com"`))
+	assert.Nil(t, ValidateSchema(schema, `15`))
+	assert.NotNil(t, ValidateSchema(schema, `"joe"`))
+	assert.NotNil(t, ValidateSchema(schema, `12`))
+	assert.NotNil(t, ValidateSchema(schema, `10`))
+
+	// unsupported keywords are rejected rather than ignored
+	err := ValidateSchema(`{"properties": {"id": {"type": "string", "contentEncoding": "base64"}}}`, `{}`)
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid JSON Schema: unsupported keyword 'contentEncoding' at '#/properties/id'", err.Error())
+
+	err = ValidateSchema(`{"type": "string", "format": "hostname"}`, `"localhost"`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unsupported format 'hostname'")
+}
+
+//TestValidateSchemaEnumObjects
+func TestValidateSchemaEnumObjects(t *testing.T) {
+
+	schema := `{"enum": [{"id": 1, "tags": [1.5, "a"]}, [1, 2]]}`
+
+	assert.Nil(t, ValidateSchema(schema, map[string]interface{}{"id": 1, "tags": []interface{}{1.5, "a"}}))
+	assert.Nil(t, ValidateSchema(schema, `{"id": 1.0, "tags": [1.50, "a"]}`))
+	assert.Nil(t, ValidateSchema(schema, []int{1, 2}))
+	assert.NotNil(t, ValidateSchema(schema, map[string]interface{}{"id": 2, "tags": []interface{}{1.5, "a"}}))
+	assert.NotNil(t, ValidateSchema(schema, `{"id": "1", "tags": [1.5, "a"]}`))
+}
@@ -2,6 +2,9 @@ package data
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 )
 
@@ -36,6 +39,25 @@ type MutableScope interface {
 	AddAttr(name string, valueType Type, value interface{}) *Attribute
 }
 
+// SnapshotScope is a scope that can take a snapshot of its attributes
+type SnapshotScope interface {
+	Scope
+
+	// Snapshot gets a deep copy of the attributes of the scope, later changes
+	// to the scope don't affect the snapshot and vice versa
+	Snapshot() map[string]*Attribute
+}
+
+func snapshotAttrs(attrs map[string]*Attribute) map[string]*Attribute {
+
+	snapshot := make(map[string]*Attribute, len(attrs))
+	for name, attr := range attrs {
+		snapshot[name] = DeepCloneAttribute(attr.Name(), attr)
+	}
+
+	return snapshot
+}
+
 // SimpleScope is a basic implementation of a scope
 type SimpleScope struct {
 	parentScope Scope
@@ -63,12 +85,17 @@ func newSimpleScope(attrs []*Attribute, parentScope Scope) *SimpleScope {
 	return scope
 }
 
-// NewSimpleScopeFromMap creates a new SimpleScope
+// NewSimpleScopeFromMap creates a new SimpleScope, the map is copied so adding attributes
+// to the scope doesn't modify it
 func NewSimpleScopeFromMap(attrs map[string]*Attribute, parentScope Scope) *SimpleScope {
 
 	scope := &SimpleScope{
 		parentScope: parentScope,
-		attrs:       attrs,
+		attrs:       make(map[string]*Attribute, len(attrs)),
+	}
+
+	for name, attr := range attrs {
+		scope.attrs[name] = attr
 	}
 
 	return scope
@@ -119,10 +146,15 @@ func (s *SimpleScope) AddAttr(name string, valueType Type, value interface{}) *A
 	return attr
 }
 
+// Snapshot implements SnapshotScope.Snapshot, the attributes of the parent scope aren't included
+func (s *SimpleScope) Snapshot() map[string]*Attribute {
+	return snapshotAttrs(s.attrs)
+}
+
 // SimpleSyncScope is a basic implementation of a synchronized scope
 type SimpleSyncScope struct {
 	scope *SimpleScope
-	mutex sync.Mutex
+	mutex sync.RWMutex
 }
 
 // NewSimpleSyncScope creates a new SimpleSyncScope
@@ -137,8 +169,8 @@ func NewSimpleSyncScope(attrs []*Attribute, parentScope Scope) MutableScope {
 // GetAttr implements Scope.GetAttr
 func (s *SimpleSyncScope) GetAttr(name string) (value *Attribute, exists bool) {
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 
 	return s.scope.GetAttr(name)
 }
@@ -161,6 +193,15 @@ func (s *SimpleSyncScope) AddAttr(name string, valueType Type, value interface{}
 	return s.scope.AddAttr(name, valueType, value)
 }
 
+// Snapshot implements SnapshotScope.Snapshot
+func (s *SimpleSyncScope) Snapshot() map[string]*Attribute {
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.scope.Snapshot()
+}
+
 var (
 	globalScope = NewSimpleSyncScope(nil, nil)
 )
@@ -176,7 +217,7 @@ type FixedScope struct {
 	metadata map[string]*Attribute
 }
 
-// NewFixedScope creates a new SimpleScope
+// NewFixedScope creates a new SimpleScope, attributes with a default value in their metadata are set to it
 func NewFixedScope(metadata map[string]*Attribute) *FixedScope {
 
 	scope := &FixedScope{
@@ -185,6 +226,7 @@ func NewFixedScope(metadata map[string]*Attribute) *FixedScope {
 	}
 
 	scope.metadata = metadata
+	scope.setDefaults()
 
 	return scope
 }
@@ -195,10 +237,49 @@ func NewFixedScopeFromMap(metadata map[string]*Attribute) *FixedScope {
 		metadata: metadata,
 		attrs:    make(map[string]*Attribute),
 	}
+	scope.setDefaults()
 
 	return scope
 }
 
+func (s *FixedScope) setDefaults() {
+
+	for name, metaAttr := range s.metadata {
+		if md := metaAttr.Metadata(); md != nil && md.Default != nil {
+			attr, err := NewAttribute(name, metaAttr.Type(), md.Default)
+			if err == nil {
+				attr.metadata = md
+				s.attrs[name] = attr
+			}
+		}
+	}
+}
+
+// Validate validates the attributes of the scope against their metadata, an
+// error is returned if a required attribute hasn't been set
+func (s *FixedScope) Validate() error {
+
+	var names []string
+	for name := range s.metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+
+		var value interface{}
+		if attr, found := s.attrs[name]; found {
+			value = attr.Value()
+		}
+
+		if err := ValidateValue(s.metadata[name], value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GetAttr implements Scope.GetAttr
 func (s *FixedScope) GetAttr(name string) (attr *Attribute, exists bool) {
 
@@ -211,18 +292,38 @@ func (s *FixedScope) GetAttrs() map[string]*Attribute {
 	return s.attrs
 }
 
+// Snapshot implements SnapshotScope.Snapshot
+func (s *FixedScope) Snapshot() map[string]*Attribute {
+	return snapshotAttrs(s.attrs)
+}
+
 // SetAttrValue implements Scope.SetAttrValue
 func (s *FixedScope) SetAttrValue(name string, value interface{}) error {
 
 	attr, found := s.attrs[name]
 
 	if found {
+		if md := attr.Metadata(); md != nil {
+			coerced, err := CoerceToValue(value, attr.Type())
+			if err != nil {
+				return err
+			}
+			if err := md.Validate(coerced); err != nil {
+				return fmt.Errorf("invalid '%s': %s", name, err.Error())
+			}
+		}
 		attr.SetValue(value)
 		return nil
 	} else {
 		metaAttr, found := s.metadata[name]
 		if found {
 			attr, err := NewAttribute(name, metaAttr.Type(), value)
+			if err == nil {
+				if vErr := metaAttr.Metadata().Validate(attr.Value()); vErr != nil {
+					return fmt.Errorf("invalid '%s': %s", name, vErr.Error())
+				}
+			}
+			attr.metadata = metaAttr.Metadata()
 			s.attrs[name] = attr
 			return err
 		}
@@ -230,3 +331,225 @@ func (s *FixedScope) SetAttrValue(name string, value interface{}) error {
 
 	return errors.New("attribute not in scope")
 }
+
+// ImmutableScope is a read-only scope, its attributes can't be set. The attributes returned
+// share their values with the scope, so they have to be treated as read-only views, values
+// that are updated in place have to be copied first (see CopyForUpdate)
+type ImmutableScope struct {
+	parentScope Scope
+	attrs       map[string]*Attribute
+}
+
+// NewImmutableScope creates a new ImmutableScope
+func NewImmutableScope(attrs []*Attribute, parentScope Scope) *ImmutableScope {
+
+	scope := &ImmutableScope{
+		parentScope: parentScope,
+		attrs:       make(map[string]*Attribute, len(attrs)),
+	}
+
+	for _, attr := range attrs {
+		scope.attrs[attr.Name()] = attr
+	}
+
+	return scope
+}
+
+// NewImmutableScopeFromMap creates a new ImmutableScope
+func NewImmutableScopeFromMap(attrs map[string]*Attribute, parentScope Scope) *ImmutableScope {
+
+	scope := &ImmutableScope{
+		parentScope: parentScope,
+		attrs:       make(map[string]*Attribute, len(attrs)),
+	}
+
+	for name, attr := range attrs {
+		scope.attrs[name] = attr
+	}
+
+	return scope
+}
+
+// GetAttr implements Scope.GetAttr
+func (s *ImmutableScope) GetAttr(name string) (attr *Attribute, exists bool) {
+
+	attr, found := s.attrs[name]
+
+	if found {
+		return attr, true
+	}
+
+	if s.parentScope != nil {
+		return s.parentScope.GetAttr(name)
+	}
+
+	return nil, false
+}
+
+// SetAttrValue implements Scope.SetAttrValue
+func (s *ImmutableScope) SetAttrValue(name string, value interface{}) error {
+	return fmt.Errorf("unable to set attribute '%s': scope is read-only", name)
+}
+
+// Snapshot implements SnapshotScope.Snapshot
+func (s *ImmutableScope) Snapshot() map[string]*Attribute {
+	return snapshotAttrs(s.attrs)
+}
+
+// CopyOnWriteScope is a scope on top of a set of attributes that is never modified, an attribute
+// is copied to the scope the first time it is accessed, so changes only affect the scope. The scope
+// can be read concurrently, but like a SimpleScope setting attribute values isn't synchronized.
+type CopyOnWriteScope struct {
+	parentScope Scope
+	base        map[string]*Attribute
+
+	mutex sync.Mutex
+	attrs map[string]*Attribute
+}
+
+// NewCopyOnWriteScope creates a new CopyOnWriteScope
+func NewCopyOnWriteScope(base map[string]*Attribute, parentScope Scope) *CopyOnWriteScope {
+
+	return &CopyOnWriteScope{
+		parentScope: parentScope,
+		base:        base,
+		attrs:       make(map[string]*Attribute),
+	}
+}
+
+func (s *CopyOnWriteScope) getOwnAttr(name string) (*Attribute, bool) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if attr, found := s.attrs[name]; found {
+		return attr, true
+	}
+
+	if baseAttr, found := s.base[name]; found {
+		attr := DeepCloneAttribute(baseAttr.Name(), baseAttr)
+		s.attrs[name] = attr
+		return attr, true
+	}
+
+	return nil, false
+}
+
+// GetAttr implements Scope.GetAttr
+func (s *CopyOnWriteScope) GetAttr(name string) (attr *Attribute, exists bool) {
+
+	if attr, found := s.getOwnAttr(name); found {
+		return attr, true
+	}
+
+	if s.parentScope != nil {
+		return s.parentScope.GetAttr(name)
+	}
+
+	return nil, false
+}
+
+// SetAttrValue implements Scope.SetAttrValue
+func (s *CopyOnWriteScope) SetAttrValue(name string, value interface{}) error {
+
+	if attr, found := s.getOwnAttr(name); found {
+		return attr.SetValue(value)
+	}
+
+	return errors.New("attribute not in scope")
+}
+
+// AddAttr implements MutableScope.AddAttr
+func (s *CopyOnWriteScope) AddAttr(name string, valueType Type, value interface{}) *Attribute {
+
+	attr, found := s.getOwnAttr(name)
+
+	if found {
+		attr.SetValue(value)
+	} else {
+		//todo handle error, add error to AddAttr signature
+		attr, _ = NewAttribute(name, valueType, value)
+		s.mutex.Lock()
+		s.attrs[name] = attr
+		s.mutex.Unlock()
+	}
+
+	return attr
+}
+
+// Snapshot implements SnapshotScope.Snapshot, the attributes of the parent scope aren't included
+func (s *CopyOnWriteScope) Snapshot() map[string]*Attribute {
+
+	s.mutex.Lock()
+	merged := make(map[string]*Attribute, len(s.base)+len(s.attrs))
+	for name, attr := range s.base {
+		merged[name] = attr
+	}
+	for name, attr := range s.attrs {
+		merged[name] = attr
+	}
+	s.mutex.Unlock()
+
+	return snapshotAttrs(merged)
+}
+
+// MappingScope is the output scope of a mapper while it applies its mappings, it keeps track of the
+// attribute values the mapper already copied so every value is copied at most once before it is
+// updated in place, no matter how many of its fields are mapped
+type MappingScope struct {
+	Scope
+	owned map[string]interface{}
+}
+
+// NewMappingScope creates a new MappingScope
+func NewMappingScope(scope Scope) *MappingScope {
+	return &MappingScope{Scope: scope, owned: make(map[string]interface{})}
+}
+
+// CopyForUpdate returns a copy of the attribute value that can be updated in place, since the value
+// might be shared with other scopes. The value isn't copied again if it is owned by a MappingScope.
+func CopyForUpdate(scope Scope, name string, value interface{}) interface{} {
+
+	if ms, ok := scope.(*MappingScope); ok {
+		if sameValue(ms.owned[name], value) {
+			return value
+		}
+
+		valueCopy := DeepCopy(value)
+		ms.owned[name] = valueCopy
+		return valueCopy
+	}
+
+	return DeepCopy(value)
+}
+
+// MarkUpdated marks the updated value of the attribute as owned by the MappingScope, so it
+// can be updated in place by the following mappings
+func MarkUpdated(scope Scope, name string, value interface{}) {
+
+	if ms, ok := scope.(*MappingScope); ok {
+		ms.owned[name] = value
+	}
+}
+
+// sameValue indicates if both values are the same map, slice or pointer
+func sameValue(a, b interface{}) bool {
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+
+	switch va.Kind() {
+	case reflect.Map, reflect.Ptr:
+		return va.Pointer() == vb.Pointer()
+	case reflect.Slice:
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	}
+
+	return false
+}
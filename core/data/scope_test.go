@@ -0,0 +1,194 @@
+package data
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//TestSimpleSyncScopeConcurrentAccess
+func TestSimpleSyncScopeConcurrentAccess(t *testing.T) {
+
+	scope := NewSimpleSyncScope(nil, nil)
+	scope.AddAttr("counter", TypeInteger, 0)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				scope.SetAttrValue("counter", j)
+				scope.AddAttr("other", TypeString, "value")
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, exists := scope.GetAttr("counter")
+				assert.True(t, exists)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	attr, exists := scope.GetAttr("counter")
+	assert.True(t, exists)
+	assert.Equal(t, 99, attr.Value())
+}
+
+//TestSimpleSyncScopeReturnsAttr
+func TestSimpleSyncScopeReturnsAttr(t *testing.T) {
+
+	scope := NewSimpleSyncScope(nil, nil)
+	added := scope.AddAttr("name", TypeString, "orig")
+
+	attr, _ := scope.GetAttr("name")
+	assert.True(t, added == attr)
+
+	attr.SetValue("updated")
+
+	attr, _ = scope.GetAttr("name")
+	assert.Equal(t, "updated", attr.Value())
+
+	_, exists := scope.GetAttr("missing")
+	assert.False(t, exists)
+}
+
+//TestSimpleScopeFromMapCopiesMap
+func TestSimpleScopeFromMapCopiesMap(t *testing.T) {
+
+	attrs := map[string]*Attribute{}
+	scope := NewSimpleScopeFromMap(attrs, nil)
+	scope.AddAttr("added", TypeString, "value")
+
+	_, exists := attrs["added"]
+	assert.False(t, exists)
+}
+
+//TestScopeSnapshot
+func TestScopeSnapshot(t *testing.T) {
+
+	obj, _ := CoerceToObject(`{"items":[1,2]}`)
+	attr, _ := NewAttribute("obj", TypeObject, obj)
+	scope := NewSimpleScope([]*Attribute{attr}, nil).(*SimpleScope)
+
+	snapshot := scope.Snapshot()
+
+	err := PathSetValue(obj, ".items[0]", 5)
+	assert.Nil(t, err)
+	scope.SetAttrValue("obj", map[string]interface{}{})
+
+	assert.Equal(t, map[string]interface{}{"items": []interface{}{1.0, 2.0}}, snapshot["obj"].Value())
+}
+
+//TestImmutableScope
+func TestImmutableScope(t *testing.T) {
+
+	obj, _ := CoerceToObject(`{"key":"value"}`)
+	attr, _ := NewAttribute("obj", TypeObject, obj)
+	scope := NewImmutableScope([]*Attribute{attr}, nil)
+
+	readAttr, exists := scope.GetAttr("obj")
+	assert.True(t, exists)
+	assert.Equal(t, "value", readAttr.Value().(map[string]interface{})["key"])
+
+	assert.NotNil(t, scope.SetAttrValue("obj", map[string]interface{}{}))
+
+	readAttr, _ = scope.GetAttr("obj")
+	assert.Equal(t, "value", readAttr.Value().(map[string]interface{})["key"])
+}
+
+//TestMappingScopeCopyForUpdate
+func TestMappingScopeCopyForUpdate(t *testing.T) {
+
+	shared := map[string]interface{}{"key": "value"}
+	attr, _ := NewAttribute("obj", TypeObject, shared)
+	scope := NewMappingScope(NewSimpleScope([]*Attribute{attr}, nil))
+
+	// the shared value is copied the first time
+	value := CopyForUpdate(scope, "obj", shared).(map[string]interface{})
+	value["key"] = "modified"
+	scope.SetAttrValue("obj", value)
+	MarkUpdated(scope, "obj", value)
+	assert.Equal(t, "value", shared["key"])
+
+	// the owned value isn't copied again
+	readAttr, _ := scope.GetAttr("obj")
+	again := CopyForUpdate(scope, "obj", readAttr.Value()).(map[string]interface{})
+	again["other"] = 1
+	assert.Equal(t, 1, value["other"])
+
+	// a new shared value is copied again
+	scope.SetAttrValue("obj", shared)
+	copied := CopyForUpdate(scope, "obj", shared).(map[string]interface{})
+	copied["key"] = "modified"
+	assert.Equal(t, "value", shared["key"])
+
+	// scopes that aren't mapping scopes always get a copy
+	plain := CopyForUpdate(NewSimpleScope(nil, nil), "obj", shared).(map[string]interface{})
+	plain["key"] = "modified"
+	assert.Equal(t, "value", shared["key"])
+}
+
+//TestCopyOnWriteScope
+func TestCopyOnWriteScope(t *testing.T) {
+
+	obj, _ := CoerceToObject(`{"key":"value"}`)
+	attr, _ := NewAttribute("obj", TypeObject, obj)
+	base := map[string]*Attribute{"obj": attr}
+
+	scope := NewCopyOnWriteScope(base, nil)
+
+	readAttr, _ := scope.GetAttr("obj")
+	readAttr.Value().(map[string]interface{})["key"] = "modified"
+
+	readAttr, _ = scope.GetAttr("obj")
+	assert.Equal(t, "modified", readAttr.Value().(map[string]interface{})["key"])
+	assert.Equal(t, "value", obj["key"])
+
+	assert.Nil(t, scope.SetAttrValue("obj", `{"key":"set"}`))
+	scope.AddAttr("added", TypeInteger, 1)
+	assert.NotNil(t, scope.SetAttrValue("missing", 1))
+
+	assert.Equal(t, attr, base["obj"])
+	assert.Equal(t, 1, len(base))
+
+	snapshot := scope.Snapshot()
+	assert.Equal(t, 2, len(snapshot))
+	assert.Equal(t, map[string]interface{}{"key": "set"}, snapshot["obj"].Value())
+}
+
+//TestCopyOnWriteScopeConcurrentRead
+func TestCopyOnWriteScopeConcurrentRead(t *testing.T) {
+
+	base := make(map[string]*Attribute)
+	for _, name := range []string{"a", "b", "c"} {
+		base[name], _ = NewAttribute(name, TypeInteger, 1)
+	}
+
+	scope := NewCopyOnWriteScope(base, nil)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			for _, name := range []string{"a", "b", "c"} {
+				attr, exists := scope.GetAttr(name)
+				assert.True(t, exists)
+				assert.Equal(t, 1, attr.Value())
+			}
+			scope.Snapshot()
+		}()
+	}
+
+	wg.Wait()
+}
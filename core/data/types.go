@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Type denotes a data type
@@ -19,6 +21,10 @@ const (
 	TypeArray
 	TypeParams
 	TypeComplexObject
+	TypeDateTime
+	TypeBytes
+	TypeLong
+	TypeDecimal
 )
 
 var types = [...]string{
@@ -30,8 +36,11 @@ var types = [...]string{
 	"object",
 	"array",
 	"params",
-	"any",
 	"complex_object",
+	"datetime",
+	"bytes",
+	"long",
+	"decimal",
 }
 
 var typeMap = map[string]Type{
@@ -44,18 +53,141 @@ var typeMap = map[string]Type{
 	"array":          TypeArray,
 	"params":         TypeParams,
 	"complex_object": TypeComplexObject,
+	"datetime":       TypeDateTime,
+	"bytes":          TypeBytes,
+	"long":           TypeLong,
+	"decimal":        TypeDecimal,
+}
+
+// paramType is a parameterized type, ex. "array<integer>" or "map<string>"
+type paramType struct {
+	name     string
+	baseType Type
+	elemType Type
 }
 
+var (
+	paramTypesMu  sync.RWMutex
+	paramTypes    []*paramType
+	paramTypesMap = make(map[string]Type)
+)
+
 func (t Type) String() string {
-	return types[t]
+
+	if int(t) < len(types) {
+		return types[t]
+	}
+
+	if pt := getParamType(t); pt != nil {
+		return pt.name
+	}
+
+	return "unknown"
+}
+
+// BaseType gets the base type of the type, TypeArray for "array<...>" types and TypeObject for "map<...>" types
+func (t Type) BaseType() Type {
+
+	if pt := getParamType(t); pt != nil {
+		return pt.baseType
+	}
+
+	return t
+}
+
+// ElemType gets the element type of an "array<...>" or "map<...>" type
+func (t Type) ElemType() (Type, bool) {
+
+	if pt := getParamType(t); pt != nil {
+		return pt.elemType, true
+	}
+
+	return TypeAny, false
 }
 
-// ToTypeEnum get the data type that corresponds to the specified name
+// ArrayOf gets the type of an array with elements of the specified type
+func ArrayOf(elemType Type) Type {
+	return toParamType(TypeArray, elemType)
+}
+
+// MapOf gets the type of a map with values of the specified type
+func MapOf(elemType Type) Type {
+	return toParamType(TypeObject, elemType)
+}
+
+func getParamType(t Type) *paramType {
+
+	idx := int(t) - len(types)
+	if idx < 0 {
+		return nil
+	}
+
+	paramTypesMu.RLock()
+	defer paramTypesMu.RUnlock()
+
+	if idx >= len(paramTypes) {
+		return nil
+	}
+
+	return paramTypes[idx]
+}
+
+func toParamType(baseType Type, elemType Type) Type {
+
+	prefix := "array"
+	if baseType == TypeObject {
+		prefix = "map"
+	}
+	name := prefix + "<" + elemType.String() + ">"
+
+	paramTypesMu.Lock()
+	defer paramTypesMu.Unlock()
+
+	if t, exists := paramTypesMap[name]; exists {
+		return t
+	}
+
+	t := Type(len(types) + len(paramTypes))
+	paramTypes = append(paramTypes, &paramType{name: name, baseType: baseType, elemType: elemType})
+	paramTypesMap[name] = t
+
+	return t
+}
+
+// ToTypeEnum get the data type that corresponds to the specified name, parameterized
+// array and map types are specified as "array<integer>" or "map<string>"
 func ToTypeEnum(typeStr string) (Type, bool) {
 
-	dataType, found := typeMap[strings.ToLower(typeStr)]
+	typeStr = strings.Replace(strings.ToLower(typeStr), " ", "", -1)
+
+	if dataType, found := typeMap[typeStr]; found {
+		return dataType, true
+	}
+
+	if !strings.HasSuffix(typeStr, ">") {
+		return TypeAny, false
+	}
+
+	var baseType Type
+	var elemStr string
+
+	switch {
+	case strings.HasPrefix(typeStr, "array<"):
+		baseType = TypeArray
+		elemStr = typeStr[len("array<") : len(typeStr)-1]
+	case strings.HasPrefix(typeStr, "map<"):
+		baseType = TypeObject
+		elemStr = typeStr[len("map<") : len(typeStr)-1]
+	default:
+		return TypeAny, false
+	}
+
+	elemType, found := ToTypeEnum(elemStr)
+	if !found {
+		return TypeAny, false
+	}
 
-	return dataType, found
+	return toParamType(baseType, elemType), true
 }
 
 // GetType get the Type of the supplied value
@@ -66,6 +198,10 @@ func GetType(val interface{}) (Type, error) {
 		return TypeString, nil
 	case int:
 		return TypeInteger, nil
+	case int64:
+		return TypeLong, nil
+	case *Decimal:
+		return TypeDecimal, nil
 	case float64:
 		return TypeNumber, nil
 	case json.Number:
@@ -78,6 +214,10 @@ func GetType(val interface{}) (Type, error) {
 		return TypeArray, nil
 	case ComplexObject:
 		return TypeComplexObject, nil
+	case time.Time:
+		return TypeDateTime, nil
+	case []byte:
+		return TypeBytes, nil
 	default:
 		return TypeAny, fmt.Errorf("unable to determine type of %#v", t)
 	}
@@ -86,7 +226,7 @@ func GetType(val interface{}) (Type, error) {
 func IsSimpleType(val interface{}) bool {
 
 	switch val.(type) {
-	case string, int, float64, json.Number, bool:
+	case string, int, int64, float64, json.Number, *Decimal, bool:
 		return true
 	default:
 		return false
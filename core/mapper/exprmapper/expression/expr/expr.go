@@ -1,9 +1,11 @@
 package expr
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"reflect"
+	"time"
 
 	"fmt"
 
@@ -363,6 +365,10 @@ func equals(left interface{}, right interface{}) (bool, error) {
 		return false, nil
 	}
 
+	if cmp, ok, err := losslessCompare(left, right); ok {
+		return cmp == 0, err
+	}
+
 	rightValue, err := convertRightValueToLeftType(left, right)
 	if err != nil {
 		return false, err
@@ -370,7 +376,21 @@ func equals(left interface{}, right interface{}) (bool, error) {
 
 	log.Debugf("Right expression value [%s]", rightValue)
 
-	return left == rightValue, nil
+	return equalValues(left, rightValue), nil
+}
+
+// equalValues compares the values, date-times and bytes can't be compared using ==
+func equalValues(left interface{}, right interface{}) bool {
+	switch le := left.(type) {
+	case time.Time:
+		re, ok := right.(time.Time)
+		return ok && le.Equal(re)
+	case []byte:
+		re, ok := right.([]byte)
+		return ok && bytes.Equal(le, re)
+	}
+
+	return left == right
 }
 
 func convertRightValueToLeftType(left interface{}, right interface{}) (interface{}, error) {
@@ -406,6 +426,16 @@ func convertRightValueToLeftType(left interface{}, right interface{}) (interface
 		if err != nil {
 			err = fmt.Errorf("Convert right expression to type boolean failed, due to %s", err.Error())
 		}
+	case time.Time:
+		rightValue, err = data.CoerceToDateTime(right)
+		if err != nil {
+			err = fmt.Errorf("Convert right expression to type datetime failed, due to %s", err.Error())
+		}
+	case []byte:
+		rightValue, err = data.CoerceToBytes(right)
+		if err != nil {
+			err = fmt.Errorf("Convert right expression to type bytes failed, due to %s", err.Error())
+		}
 	default:
 		err = fmt.Errorf("Unsupport type to compare now")
 	}
@@ -424,6 +454,10 @@ func notEquals(left interface{}, right interface{}) (bool, error) {
 		return true, nil
 	}
 
+	if cmp, ok, err := losslessCompare(left, right); ok {
+		return cmp != 0, err
+	}
+
 	rightValue, err := convertRightValueToLeftType(left, right)
 	if err != nil {
 		return false, err
@@ -431,7 +465,7 @@ func notEquals(left interface{}, right interface{}) (bool, error) {
 
 	log.Debugf("Right expression value [%s]", rightValue)
 
-	return left != rightValue, nil
+	return !equalValues(left, rightValue), nil
 
 }
 
@@ -446,6 +480,10 @@ func gt(left interface{}, right interface{}, includeEquals bool) (bool, error) {
 		return false, nil
 	}
 
+	if cmp, ok, err := losslessCompare(left, right); ok {
+		return cmp > 0 || (includeEquals && cmp == 0), err
+	}
+
 	rightType := getType(right)
 	log.Infof("Right type: %s", rightType.String())
 	switch le := left.(type) {
@@ -484,6 +522,17 @@ func gt(left interface{}, right interface{}, includeEquals bool) (bool, error) {
 		} else {
 			return le > rightValue, nil
 		}
+	case time.Time:
+		rightValue, err := data.CoerceToDateTime(right)
+		if err != nil {
+			return false, fmt.Errorf("Convert right expression to type datetime failed, due to %s", err.Error())
+		}
+		if includeEquals {
+			return !le.Before(rightValue), nil
+
+		} else {
+			return le.After(rightValue), nil
+		}
 	default:
 		return false, errors.New("Unknow type to equals" + getType(left).String())
 	}
@@ -502,6 +551,10 @@ func lt(left interface{}, right interface{}, includeEquals bool) (bool, error) {
 		return false, nil
 	}
 
+	if cmp, ok, err := losslessCompare(left, right); ok {
+		return cmp < 0 || (includeEquals && cmp == 0), err
+	}
+
 	switch le := left.(type) {
 	case int:
 		rightValue, err := data.CoerceToInteger(right)
@@ -536,6 +589,17 @@ func lt(left interface{}, right interface{}, includeEquals bool) (bool, error) {
 		} else {
 			return le < rightValue, nil
 		}
+	case time.Time:
+		rightValue, err := data.CoerceToDateTime(right)
+		if err != nil {
+			return false, fmt.Errorf("Convert right expression to type datetime failed, due to %s", err.Error())
+		}
+		if includeEquals {
+			return !le.After(rightValue), nil
+
+		} else {
+			return le.Before(rightValue), nil
+		}
 	default:
 		return false, errors.New("Unknow type to equals" + getType(left).String())
 	}
@@ -589,6 +653,10 @@ func additon(left interface{}, right interface{}) (interface{}, error) {
 		return false, nil
 	}
 
+	if result, ok, err := losslessArithmetic(left, ADDITION, right); ok {
+		return result, err
+	}
+
 	switch le := left.(type) {
 	case int:
 		rightValue, err := data.CoerceToInteger(right)
@@ -629,6 +697,10 @@ func sub(left interface{}, right interface{}) (interface{}, error) {
 		return false, nil
 	}
 
+	if result, ok, err := losslessArithmetic(left, SUBTRACTION, right); ok {
+		return result, err
+	}
+
 	switch le := left.(type) {
 	case int:
 		rightValue, err := data.CoerceToInteger(right)
@@ -669,6 +741,10 @@ func multiplication(left interface{}, right interface{}) (interface{}, error) {
 		return false, nil
 	}
 
+	if result, ok, err := losslessArithmetic(left, MULTIPLICATION, right); ok {
+		return result, err
+	}
+
 	switch le := left.(type) {
 	case int:
 		rightValue, err := data.CoerceToInteger(right)
@@ -709,25 +785,32 @@ func div(left interface{}, right interface{}) (interface{}, error) {
 		return false, nil
 	}
 
+	if result, ok, err := losslessArithmetic(left, DIVISION, right); ok {
+		return result, err
+	}
+
 	switch le := left.(type) {
 	case int:
 		rightValue, err := data.CoerceToInteger(right)
 		if err != nil {
 			return false, fmt.Errorf("Convert right expression to type int failed, due to %s", err.Error())
 		}
-		return le + rightValue, nil
-	case int64:
-		rightValue, err := data.CoerceToInteger(right)
-		if err != nil {
-			return false, fmt.Errorf("Convert right expression to type int failed, due to %s", err.Error())
+		if rightValue == 0 {
+			return false, errors.New("Division by zero")
 		}
-		return int(le) + rightValue, nil
+		if le%rightValue == 0 {
+			return le / rightValue, nil
+		}
+		return float64(le) / float64(rightValue), nil
 	case float64:
 		rightValue, err := data.CoerceToNumber(right)
 		if err != nil {
 			return false, fmt.Errorf("Convert right expression to type int failed, due to %s", err.Error())
 		}
-		return le + rightValue, nil
+		if rightValue == 0 {
+			return false, errors.New("Division by zero")
+		}
+		return le / rightValue, nil
 	default:
 		return false, errors.New("Unknow type to equals" + getType(left).String())
 	}
@@ -0,0 +1,115 @@
+package expr
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareDateTime(t *testing.T) {
+
+	dt := time.Date(2018, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	result, err := equals(dt, "2018-03-01T10:30:00Z")
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = notEquals(dt, "2018-03-01T10:30:00Z")
+	assert.Nil(t, err)
+	assert.False(t, result)
+
+	result, err = gt(dt, "2018-03-01T00:00:00Z", false)
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = lt(dt, "2018-03-01T10:30:00Z", true)
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = lt(dt, "2018-03-01T10:30:00Z", false)
+	assert.Nil(t, err)
+	assert.False(t, result)
+}
+
+func TestCompareBytes(t *testing.T) {
+
+	result, err := equals([]byte("hello"), "aGVsbG8=")
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = notEquals([]byte("hello"), []byte("world"))
+	assert.Nil(t, err)
+	assert.True(t, result)
+}
+
+func TestLosslessArithmetic(t *testing.T) {
+
+	result, err := additon(json.Number("9007199254740993"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740994), result)
+
+	result, err = multiplication(int64(4611686018427387904), 4)
+	assert.Nil(t, err)
+	assert.Equal(t, "18446744073709551616", result.(*data.Decimal).String())
+
+	price, _ := data.NewDecimal("19.99")
+	result, err = multiplication(price, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "59.97", result.(*data.Decimal).String())
+
+	result, err = additon(json.Number("0.1"), json.Number("0.2"))
+	assert.Nil(t, err)
+	assert.Equal(t, "0.3", result.(*data.Decimal).String())
+
+	result, err = sub(price, "0.99")
+	assert.Nil(t, err)
+	assert.Equal(t, "19", result.(*data.Decimal).String())
+
+	result, err = div(int64(10), 4)
+	assert.Nil(t, err)
+	assert.Equal(t, "2.5", result.(*data.Decimal).String())
+
+	_, err = div(price, 0)
+	assert.NotNil(t, err)
+}
+
+func TestDivision(t *testing.T) {
+
+	result, err := div(10, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, result)
+
+	result, err = div(10, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, 2.5, result)
+
+	result, err = div(1.5, 0.5)
+	assert.Nil(t, err)
+	assert.Equal(t, 3.0, result)
+
+	_, err = div(1, 0)
+	assert.NotNil(t, err)
+}
+
+func TestLosslessCompare(t *testing.T) {
+
+	result, err := equals(json.Number("9007199254740993"), int64(9007199254740993))
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = equals(json.Number("9007199254740993"), json.Number("9007199254740992"))
+	assert.Nil(t, err)
+	assert.False(t, result)
+
+	price, _ := data.NewDecimal("19.99")
+	result, err = gt(price, "19.98", false)
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = lt(price, 19.99, true)
+	assert.Nil(t, err)
+	assert.True(t, result)
+}
@@ -0,0 +1,98 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+)
+
+// isLosslessNumber indicates if the value is a number that can't be converted to an int or float64
+// without risking a loss of precision
+func isLosslessNumber(val interface{}) bool {
+	switch val.(type) {
+	case int64, json.Number, *data.Decimal:
+		return true
+	}
+	return false
+}
+
+// isIntegerNumber indicates if the value is a whole number type, strings are considered integers if they can be parsed as one
+func isIntegerNumber(val interface{}) bool {
+	switch t := val.(type) {
+	case int, int64:
+		return true
+	case json.Number:
+		_, err := t.Int64()
+		return err == nil
+	case string:
+		_, err := data.CoerceToLong(t)
+		return err == nil
+	}
+	return false
+}
+
+// losslessArithmetic performs the arithmetic operation using arbitrary-precision decimals if one of the operands
+// is an int64, a json.Number or a decimal. The result is an int64 if both operands are integers and the result fits
+// in an int64, otherwise it is a decimal. ok is false if neither operand requires lossless arithmetic.
+func losslessArithmetic(left interface{}, op OPERATIOR, right interface{}) (result interface{}, ok bool, err error) {
+
+	if !isLosslessNumber(left) && !isLosslessNumber(right) {
+		return nil, false, nil
+	}
+
+	leftDec, err := data.CoerceToDecimal(left)
+	if err != nil {
+		return nil, true, fmt.Errorf("Convert left expression to type decimal failed, due to %s", err.Error())
+	}
+	rightDec, err := data.CoerceToDecimal(right)
+	if err != nil {
+		return nil, true, fmt.Errorf("Convert right expression to type decimal failed, due to %s", err.Error())
+	}
+
+	var res *data.Decimal
+
+	switch op {
+	case ADDITION:
+		res = leftDec.Add(rightDec)
+	case SUBTRACTION:
+		res = leftDec.Sub(rightDec)
+	case MULTIPLICATION:
+		res = leftDec.Mul(rightDec)
+	case DIVISION:
+		res, err = leftDec.Quo(rightDec)
+		if err != nil {
+			return nil, true, err
+		}
+	default:
+		return nil, true, fmt.Errorf("Unsupported operator %s for numbers", op.String())
+	}
+
+	if isIntegerNumber(left) && isIntegerNumber(right) && res.IsInt() {
+		if i, err := res.Int64(); err == nil {
+			return i, true, nil
+		}
+	}
+
+	return res, true, nil
+}
+
+// losslessCompare compares the operands using arbitrary-precision decimals if one of them is an int64, a json.Number
+// or a decimal, ok is false if neither operand requires a lossless comparison
+func losslessCompare(left interface{}, right interface{}) (cmp int, ok bool, err error) {
+
+	if !isLosslessNumber(left) && !isLosslessNumber(right) {
+		return 0, false, nil
+	}
+
+	leftDec, err := data.CoerceToDecimal(left)
+	if err != nil {
+		return 0, true, fmt.Errorf("Convert left expression to type decimal failed, due to %s", err.Error())
+	}
+	rightDec, err := data.CoerceToDecimal(right)
+	if err != nil {
+		return 0, true, fmt.Errorf("Convert right expression to type decimal failed, due to %s", err.Error())
+	}
+
+	return leftDec.Cmp(rightDec), true, nil
+}
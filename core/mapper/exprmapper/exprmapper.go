@@ -38,8 +38,26 @@ func Map(mapping *data.MappingDef, inputScope, outputScope data.Scope, resolver
 }
 
 func GetMappingValue(mappingV interface{}, inputScope data.Scope, resolver data.Resolver) (interface{}, error) {
+
+	mapping, err := CompileMappingValue(mappingV)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapping(inputScope, resolver)
+}
+
+// CompiledMapping is a mapping value that has already been parsed, it can be evaluated
+// repeatedly and concurrently without being parsed again
+type CompiledMapping func(inputScope data.Scope, resolver data.Resolver) (interface{}, error)
+
+// CompileMappingValue parses the mapping value, an error is returned if the expression
+// or function in it isn't valid
+func CompileMappingValue(mappingV interface{}) (CompiledMapping, error) {
 	if mappingV == nil || reflect.TypeOf(mappingV).Kind() != reflect.String {
-		return mappingV, nil
+		return func(inputScope data.Scope, resolver data.Resolver) (interface{}, error) {
+			return mappingV, nil
+		}, nil
 	}
 
 	mappingValue := mappingV.(string)
@@ -50,24 +68,28 @@ func GetMappingValue(mappingV interface{}, inputScope data.Scope, resolver data.
 			return nil, fmt.Errorf("Parsing ternary expression [%s] error - %s", mappingValue, err.Error())
 		}
 
-		funcValue, err := exp.EvalWithScope(inputScope, resolver)
-		if err != nil {
-			return nil, fmt.Errorf("Execution failed for mapping [%s] due to error - %s", mappingValue, err.Error())
-		}
-		log.Debugf("Ternary expression value: %+v", funcValue)
-		return funcValue, nil
+		return func(inputScope data.Scope, resolver data.Resolver) (interface{}, error) {
+			funcValue, err := exp.EvalWithScope(inputScope, resolver)
+			if err != nil {
+				return nil, fmt.Errorf("Execution failed for mapping [%s] due to error - %s", mappingValue, err.Error())
+			}
+			log.Debugf("Ternary expression value: %+v", funcValue)
+			return funcValue, nil
+		}, nil
 	} else if expressionType == expression.EXPRESSION {
 		exp, err := expression.NewExpression(mappingValue).GetExpression()
 		if err != nil {
 			return nil, fmt.Errorf("Parsing expression [%s] error - %s", mappingValue, err.Error())
 		}
 
-		funcValue, err := exp.EvalWithScope(inputScope, resolver)
-		if err != nil {
-			return nil, fmt.Errorf("Execution failed for mapping [%s] due to error - %s", mappingValue, err.Error())
-		}
-		log.Debugf("Expression value: %+v", funcValue)
-		return funcValue, nil
+		return func(inputScope data.Scope, resolver data.Resolver) (interface{}, error) {
+			funcValue, err := exp.EvalWithScope(inputScope, resolver)
+			if err != nil {
+				return nil, fmt.Errorf("Execution failed for mapping [%s] due to error - %s", mappingValue, err.Error())
+			}
+			log.Debugf("Expression value: %+v", funcValue)
+			return funcValue, nil
+		}, nil
 
 	} else if expressionType == expression.FUNCTION {
 		log.Debugf("The mapping ref is a function")
@@ -75,35 +97,41 @@ func GetMappingValue(mappingV interface{}, inputScope data.Scope, resolver data.
 		if err != nil {
 			return nil, fmt.Errorf("Parsing function [%s] error - %s", mappingValue, err.Error())
 		}
-		funcValue, err := function.EvalWithScope(inputScope, resolver)
-		if err != nil {
-			return nil, fmt.Errorf("Execution failed for mapping [%s] due to error - %s", mappingValue, err.Error())
-		}
 
-		if funcValue != nil && len(funcValue) == 1 {
-			return funcValue[0], nil
+		return func(inputScope data.Scope, resolver data.Resolver) (interface{}, error) {
+			funcValue, err := function.EvalWithScope(inputScope, resolver)
+			if err != nil {
+				return nil, fmt.Errorf("Execution failed for mapping [%s] due to error - %s", mappingValue, err.Error())
+			}
 
-		} else if funcValue != nil && len(funcValue) > 1 {
-			return funcValue, nil
-		}
+			if funcValue != nil && len(funcValue) == 1 {
+				return funcValue[0], nil
+
+			} else if funcValue != nil && len(funcValue) > 1 {
+				return funcValue, nil
+			}
+
+			return nil, nil
+		}, nil
 
 	} else if !isMappingRef(mappingValue) {
-		log.Debugf("Mapping value is literal set directly to field")
-		log.Debugf("Mapping ref %s and value %+v", mappingValue, mappingValue)
-		return mappingValue, nil
-	} else {
+		return func(inputScope data.Scope, resolver data.Resolver) (interface{}, error) {
+			log.Debugf("Mapping value is literal set directly to field")
+			log.Debugf("Mapping ref %s and value %+v", mappingValue, mappingValue)
+			return mappingValue, nil
+		}, nil
+	}
 
+	return func(inputScope data.Scope, resolver data.Resolver) (interface{}, error) {
 		mappingref := ref.NewMappingRef(mappingValue)
-		mappingValue, err := mappingref.GetValue(inputScope, resolver)
+		value, err := mappingref.GetValue(inputScope, resolver)
 		if err != nil {
 			return nil, fmt.Errorf("Get value from ref [%s] error - %s", mappingref.GetRef(), err.Error())
 
 		}
-		log.Debugf("Mapping ref %s and value %+v", mappingValue, mappingValue)
-		return mappingValue, nil
-	}
-
-	return nil, nil
+		log.Debugf("Mapping ref %s and value %+v", value, value)
+		return value, nil
+	}, nil
 }
 
 func setValueToOutputScopde(mapTo string, outputScope data.Scope, value interface{}, resolver data.Resolver) error {
@@ -144,12 +172,18 @@ func settValueToComplexObject(toMappingRef *ref.MappingRef, fieldName string, ou
 	}
 
 	log.Debugf("Set value %+v to fields %s", value, fields)
-	complexValue, err2 := json.SetFieldValue(value, complexVlaueIn, fields)
+	// the value might be shared with the input, so set the field on a copy of it
+	complexValue, err2 := json.SetFieldValue(value, data.CopyForUpdate(outputScope, fieldName, complexVlaueIn), fields)
 	if err2 != nil {
 		return err2
 	}
 
-	return SetAttribute(fieldName, complexValue, outputScope)
+	if err := SetAttribute(fieldName, complexValue, outputScope); err != nil {
+		return err
+	}
+
+	data.MarkUpdated(outputScope, fieldName, complexValue)
+	return nil
 }
 
 func isMappingRef(mappingref string) bool {
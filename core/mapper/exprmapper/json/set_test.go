@@ -276,3 +276,23 @@ func TestConcurrentSet(t *testing.T) {
 	w.Wait()
 	assert.Nil(t, recovered)
 }
+
+func TestSetNestedArrays(t *testing.T) {
+	v, err := SetFieldValueP("a", "{}", "items[3].tags[0]")
+	assert.Nil(t, err)
+
+	v, err = SetFieldValueP("b", v, "items[-1].tags[]")
+	assert.Nil(t, err)
+
+	mappingField := &field.MappingField{HasArray: true, HasSpecialField: true}
+	mappingField.Fields = []string{"items[-1]", "tags[]"}
+	v, err = SetFieldValue("c", v, mappingField)
+	assert.Nil(t, err)
+
+	vv, _ := json.Marshal(v)
+	assert.Equal(t, `{"items":[null,null,null,{"tags":["a","b","c"]}]}`, string(vv))
+
+	value, err := GetFieldValueFromInP(v, "items[-1].tags[1]")
+	assert.Nil(t, err)
+	assert.Equal(t, "b", value)
+}
@@ -22,18 +22,13 @@ type Factory interface {
 	NewUniqueMapper(ID string, mapperDef *data.MapperDef, resolver data.Resolver) data.Mapper
 }
 
-var factory Factory
+var factory Factory = &BasicMapperFactory{}
 
-func SetFactory(factory Factory) {
-	factory = factory
+func SetFactory(mapperFactory Factory) {
+	factory = mapperFactory
 }
 
 func GetFactory() Factory {
-
-	if factory == nil {
-		factory = &BasicMapperFactory{}
-	}
-
 	return factory
 }
 
@@ -84,6 +79,8 @@ func (m *BasicMapper) Apply(inputScope data.Scope, outputScope data.Scope) error
 		return fmt.Errorf("Update mapping ref error %s", err.Error())
 	}
 
+	outputScope = data.NewMappingScope(outputScope)
+
 	//todo validate types
 	for _, mapping := range m.mappings {
 
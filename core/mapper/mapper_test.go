@@ -130,3 +130,35 @@ func TestAssignMapper(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "val1", newVal)
 }
+
+func TestAssignMapperDoesNotModifyInput(t *testing.T) {
+
+	factory := GetFactory()
+
+	mapping1 := &data.MappingDef{Type: data.MtAssign, Value: "ObjI", MapTo: "ObjO"}
+	mapping2 := &data.MappingDef{Type: data.MtAssign, Value: "SimpleI", MapTo: "ObjO.key"}
+	mapping3 := &data.MappingDef{Type: data.MtAssign, Value: "SimpleI", MapTo: "ObjO.other"}
+
+	mapper := factory.NewMapper(&data.MapperDef{Mappings: []*data.MappingDef{mapping1, mapping2, mapping3}}, nil)
+
+	objVal, _ := data.CoerceToObject("{\"key\":1}")
+	attrI1, _ := data.NewAttribute("ObjI", data.TypeObject, objVal)
+	attrI2, _ := data.NewAttribute("SimpleI", data.TypeInteger, 2)
+	inScope := data.NewImmutableScope([]*data.Attribute{attrI1, attrI2}, nil)
+
+	attrO, _ := data.NewAttribute("ObjO", data.TypeObject, nil)
+	outScope := data.NewFixedScope(map[string]*data.Attribute{attrO.Name(): attrO})
+
+	err := mapper.Apply(inScope, outScope)
+	assert.Nil(t, err)
+
+	newVal, err := (&data.BasicResolver{}).Resolve("ObjO.key", outScope)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, newVal)
+
+	newVal, err = (&data.BasicResolver{}).Resolve("ObjO.other", outScope)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, newVal)
+
+	assert.Equal(t, map[string]interface{}{"key": 1.0}, objVal)
+}
@@ -36,7 +36,7 @@ func (e *assignExpr) Eval(scope data.Scope) (interface{}, error) {
 
 	//temporary hack
 	if attr.Value() == nil {
-		switch attr.Type() {
+		switch attr.Type().BaseType() {
 		case data.TypeObject:
 			attr.SetValue(make(map[string]interface{}))
 		case data.TypeParams:
@@ -44,8 +44,21 @@ func (e *assignExpr) Eval(scope data.Scope) (interface{}, error) {
 		}
 	}
 
-	err = data.PathSetValue(attr.Value(), e.assignAttrPath, e.value)
-	return nil, err
+	// the value might be shared with the input, so update a copy of it
+	value := data.CopyForUpdate(scope, e.assignAttrName, attr.Value())
+
+	newValue, err := data.PathUpdateValue(value, e.assignAttrPath, e.value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = scope.SetAttrValue(e.assignAttrName, newValue)
+	if err != nil {
+		return nil, err
+	}
+
+	data.MarkUpdated(scope, e.assignAttrName, newValue)
+	return nil, nil
 }
 
 func NewMapperDefFromAnyArray(mappings []interface{}) (*data.MapperDef, error) {
@@ -0,0 +1,226 @@
+package trigger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/TIBCOSoftware/flogo-lib/logger"
+)
+
+// SettingCircuitBreaker is the handler setting used to configure the circuit breaker
+const SettingCircuitBreaker = "circuitBreaker"
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed the action is executed normally
+	CircuitClosed CircuitState = iota
+	// CircuitOpen the action is not executed
+	CircuitOpen
+	// CircuitHalfOpen a single trial execution is allowed
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig is the configuration of a handler circuit breaker
+type CircuitBreakerConfig struct {
+	// ConsecutiveFailures opens the circuit after the specified number of consecutive failures
+	ConsecutiveFailures int
+	// FailureRate opens the circuit when the failure rate (0-1) over the window is reached
+	FailureRate float64
+	// Window is the number of executions used to calculate the failure rate
+	Window int
+	// Cooldown is the time in milliseconds the circuit stays open
+	Cooldown int
+}
+
+// CircuitOpenError is returned when an action is not executed because the circuit is open
+type CircuitOpenError struct {
+	name  string
+	until time.Time
+}
+
+// Error implements error.Error()
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker for '%s' is open until %s", e.name, e.until.Format(time.RFC3339))
+}
+
+// Name the name of the circuit breaker
+func (e *CircuitOpenError) Name() string {
+	return e.name
+}
+
+// Until the time the circuit breaker will allow a trial execution
+func (e *CircuitOpenError) Until() time.Time {
+	return e.until
+}
+
+// NewCircuitBreakerConfig creates a CircuitBreakerConfig from the handler settings,
+// returns nil if no circuit breaker has been configured
+func NewCircuitBreakerConfig(settings map[string]interface{}) (*CircuitBreakerConfig, error) {
+
+	val, exists := settings[SettingCircuitBreaker]
+	if !exists || val == nil {
+		return nil, nil
+	}
+
+	values, err := data.CoerceToObject(val)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' setting: %s", SettingCircuitBreaker, err.Error())
+	}
+
+	config := &CircuitBreakerConfig{Window: 10, Cooldown: 30000}
+
+	for key, value := range values {
+		switch key {
+		case "consecutiveFailures":
+			config.ConsecutiveFailures, err = data.CoerceToInteger(value)
+		case "failureRate":
+			config.FailureRate, err = data.CoerceToNumber(value)
+		case "window":
+			config.Window, err = data.CoerceToInteger(value)
+		case "cooldown":
+			config.Cooldown, err = data.CoerceToInteger(value)
+		default:
+			err = fmt.Errorf("unknown setting '%s'", key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid '%s' setting: %s", SettingCircuitBreaker, err.Error())
+		}
+	}
+
+	if config.ConsecutiveFailures <= 0 && config.FailureRate <= 0 {
+		return nil, fmt.Errorf("invalid '%s' setting: 'consecutiveFailures' or 'failureRate' must be specified", SettingCircuitBreaker)
+	}
+
+	return config, nil
+}
+
+// circuitBreaker short-circuits action execution after repeated failures
+type circuitBreaker struct {
+	name   string
+	config *CircuitBreakerConfig
+
+	mu           sync.Mutex
+	state        CircuitState
+	openedAt     time.Time
+	trialRunning bool
+	consecutive  int
+	requests     int
+	failures     int
+
+	now func() time.Time
+}
+
+func newCircuitBreaker(name string, config *CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{name: name, config: config, now: time.Now}
+}
+
+// State returns the current state of the circuit breaker
+func (cb *circuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return cb.state
+}
+
+// allow determines if an execution is allowed, returns a CircuitOpenError if not
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cooldown := time.Duration(cb.config.Cooldown) * time.Millisecond
+
+	switch cb.state {
+	case CircuitOpen:
+		until := cb.openedAt.Add(cooldown)
+		if cb.now().Before(until) {
+			return &CircuitOpenError{name: cb.name, until: until}
+		}
+		cb.setState(CircuitHalfOpen)
+		cb.trialRunning = true
+	case CircuitHalfOpen:
+		if cb.trialRunning {
+			return &CircuitOpenError{name: cb.name, until: cb.now()}
+		}
+		cb.trialRunning = true
+	}
+
+	return nil
+}
+
+// record records the outcome of an allowed execution
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitHalfOpen {
+		cb.trialRunning = false
+		if err != nil {
+			cb.open()
+		} else {
+			cb.setState(CircuitClosed)
+			cb.reset()
+		}
+		return
+	}
+
+	cb.requests++
+
+	if err != nil {
+		cb.failures++
+		cb.consecutive++
+	} else {
+		cb.consecutive = 0
+	}
+
+	if cb.config.ConsecutiveFailures > 0 && cb.consecutive >= cb.config.ConsecutiveFailures {
+		cb.open()
+		return
+	}
+
+	if cb.config.FailureRate > 0 && cb.requests >= cb.config.Window {
+		rate := float64(cb.failures) / float64(cb.requests)
+		if rate >= cb.config.FailureRate {
+			cb.open()
+			return
+		}
+		// start a new window
+		cb.requests = 0
+		cb.failures = 0
+	}
+}
+
+func (cb *circuitBreaker) open() {
+	cb.openedAt = cb.now()
+	cb.setState(CircuitOpen)
+	cb.reset()
+}
+
+func (cb *circuitBreaker) reset() {
+	cb.consecutive = 0
+	cb.requests = 0
+	cb.failures = 0
+}
+
+func (cb *circuitBreaker) setState(state CircuitState) {
+	if cb.state != state {
+		logger.Infof("Circuit breaker for '%s' changed state from %s to %s", cb.name, cb.state, state)
+		cb.state = state
+	}
+}
@@ -0,0 +1,100 @@
+package trigger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//TestNewCircuitBreakerConfig
+func TestNewCircuitBreakerConfig(t *testing.T) {
+
+	config, err := NewCircuitBreakerConfig(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Nil(t, config)
+
+	config, err = NewCircuitBreakerConfig(map[string]interface{}{
+		"circuitBreaker": map[string]interface{}{"consecutiveFailures": 3.0, "cooldown": "1000"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, config.ConsecutiveFailures)
+	assert.Equal(t, 1000, config.Cooldown)
+	assert.Equal(t, 10, config.Window)
+
+	_, err = NewCircuitBreakerConfig(map[string]interface{}{
+		"circuitBreaker": map[string]interface{}{"cooldown": 1000},
+	})
+	assert.NotNil(t, err)
+
+	_, err = NewCircuitBreakerConfig(map[string]interface{}{
+		"circuitBreaker": map[string]interface{}{"failureRate": 0.5, "unknown": 1},
+	})
+	assert.NotNil(t, err)
+}
+
+//TestCircuitBreakerConsecutiveFailures
+func TestCircuitBreakerConsecutiveFailures(t *testing.T) {
+
+	now := time.Now()
+	cb := newCircuitBreaker("test", &CircuitBreakerConfig{ConsecutiveFailures: 2, Cooldown: 1000})
+	cb.now = func() time.Time { return now }
+
+	failure := errors.New("failed")
+
+	assert.Nil(t, cb.allow())
+	cb.record(failure)
+	assert.Nil(t, cb.allow())
+	cb.record(nil)
+	assert.Nil(t, cb.allow())
+	cb.record(failure)
+	assert.Nil(t, cb.allow())
+	cb.record(failure)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	err := cb.allow()
+	assert.NotNil(t, err)
+	_, isOpenErr := err.(*CircuitOpenError)
+	assert.True(t, isOpenErr)
+
+	// cooldown elapsed, single trial allowed
+	now = now.Add(time.Second)
+	assert.Nil(t, cb.allow())
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	assert.NotNil(t, cb.allow())
+
+	// trial failed
+	cb.record(failure)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	// trial succeeded
+	now = now.Add(time.Second)
+	assert.Nil(t, cb.allow())
+	cb.record(nil)
+	assert.Equal(t, CircuitClosed, cb.State())
+	assert.Nil(t, cb.allow())
+}
+
+//TestCircuitBreakerFailureRate
+func TestCircuitBreakerFailureRate(t *testing.T) {
+
+	cb := newCircuitBreaker("test", &CircuitBreakerConfig{FailureRate: 0.5, Window: 4, Cooldown: 1000})
+
+	failure := errors.New("failed")
+
+	// 1 of 4 failed, below the rate
+	cb.record(failure)
+	cb.record(nil)
+	cb.record(nil)
+	cb.record(nil)
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	// 2 of 4 failed, rate reached
+	cb.record(failure)
+	cb.record(nil)
+	cb.record(failure)
+	assert.Equal(t, CircuitClosed, cb.State())
+	cb.record(nil)
+	assert.Equal(t, CircuitOpen, cb.State())
+}
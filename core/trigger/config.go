@@ -1,6 +1,8 @@
 package trigger
 
 import (
+	"fmt"
+
 	"github.com/TIBCOSoftware/flogo-lib/core/action"
 	"github.com/TIBCOSoftware/flogo-lib/core/data"
 )
@@ -19,7 +21,9 @@ type Config struct {
 	Outputs map[string]interface{} `json:"outputs"`
 }
 
-func (c *Config) FixUp(metadata *Metadata) {
+// FixUp fixes up the configuration for backwards compatibility and coerces the outputs
+// to the types defined in the metadata
+func (c *Config) FixUp(metadata *Metadata) error {
 
 	//for backwards compatibility
 	if len(c.Output) == 0 {
@@ -35,17 +39,18 @@ func (c *Config) FixUp(metadata *Metadata) {
 			newValue, err := data.CoerceToValue(value, attr.Type())
 
 			if err != nil {
-				//todo handle error
-			} else {
-				c.Output[name] = newValue
+				return fmt.Errorf("trigger '%s': invalid output '%s' - %s", c.Id, name, err.Error())
 			}
+
+			c.Output[name] = newValue
 		}
 	}
 
 	// fix up handler outputs
-	for _, hc := range c.Handlers {
+	for i, hc := range c.Handlers {
 
 		hc.parent = c
+		hc.index = i
 
 		//for backwards compatibility
 		if len(hc.Output) == 0 {
@@ -61,13 +66,15 @@ func (c *Config) FixUp(metadata *Metadata) {
 				newValue, err := data.CoerceToValue(value, attr.Type())
 
 				if err != nil {
-					//todo handle error
-				} else {
-					hc.Output[name] = newValue
+					return fmt.Errorf("trigger '%s': invalid output '%s' of handler %d - %s", c.Id, name, i, err.Error())
 				}
+
+				hc.Output[name] = newValue
 			}
 		}
 	}
+
+	return nil
 }
 
 func (c *Config) GetSetting(setting string) string {
@@ -89,10 +96,15 @@ func (c *Config) GetSetting(setting string) string {
 
 type HandlerConfig struct {
 	parent   *Config
+	index    int
 	Settings map[string]interface{} `json:"settings"`
 	Output   map[string]interface{} `json:"output"`
 	Action   *action.Config
 
+	// Condition is an optional expression evaluated against the trigger output to determine
+	// if the handler should handle an event, ex. `$trigger.method == "GET"`, see Dispatch
+	Condition string `json:"condition,omitempty"`
+
 	//for backwards compatibility
 	ActionId             string                 `json:"actionId"`
 	ActionMappings       *data.IOMappings       `json:"actionMappings,omitempty"`
@@ -101,6 +113,30 @@ type HandlerConfig struct {
 	ActionInputMappings  []*data.MappingDef     `json:"actionInputMappings,omitempty"`
 }
 
+// Id returns the id of the handler, which is the trigger id followed by the index of the handler
+func (hc *HandlerConfig) Id() string {
+
+	if hc.parent == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s#%d", hc.parent.Id, hc.index)
+}
+
+// name returns a name to identify the handler in logs and errors
+func (hc *HandlerConfig) name() string {
+
+	if hc.parent != nil && hc.parent.Id != "" {
+		return hc.Id()
+	}
+
+	if hc.Action != nil {
+		return hc.Action.Ref
+	}
+
+	return ""
+}
+
 func (hc *HandlerConfig) GetSetting(setting string) string {
 
 	val, exists := data.GetValueWithResolver(hc.Settings, setting)
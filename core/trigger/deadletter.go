@@ -0,0 +1,333 @@
+package trigger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/TIBCOSoftware/flogo-lib/logger"
+	"github.com/TIBCOSoftware/flogo-lib/util"
+)
+
+const (
+	// DeadLetterReasonFailed indicates the action failed
+	DeadLetterReasonFailed = "failed"
+	// DeadLetterReasonRejected indicates the action wasn't executed because a limit
+	// was exceeded or the circuit was open
+	DeadLetterReasonRejected = "rejected"
+)
+
+// DeadLetter is a trigger event that failed to be handled
+type DeadLetter struct {
+	Id        string                 `json:"id"`
+	HandlerId string                 `json:"handlerId"`
+	Data      map[string]interface{} `json:"data"`
+	Error     string                 `json:"error"`
+	Reason    string                 `json:"reason"`
+	Attempts  int                    `json:"attempts"`
+	Replays   int                    `json:"replays,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// DeadLetterStore stores the trigger events that failed to be handled
+type DeadLetterStore interface {
+
+	// Add adds a dead letter to the store
+	Add(letter *DeadLetter) error
+
+	// List lists the dead letters in the store
+	List() ([]*DeadLetter, error)
+
+	// Remove removes the dead letter with the specified id from the store
+	Remove(id string) error
+}
+
+var (
+	deadLetterStoreMu sync.RWMutex
+	deadLetterStore   DeadLetterStore
+	deadLetterIdGen   *util.Generator
+)
+
+func init() {
+	deadLetterIdGen, _ = util.NewGenerator()
+}
+
+// SetDeadLetterStore sets the store used for failed trigger events, nil disables dead letter handling
+func SetDeadLetterStore(store DeadLetterStore) {
+	deadLetterStoreMu.Lock()
+	defer deadLetterStoreMu.Unlock()
+
+	deadLetterStore = store
+}
+
+// GetDeadLetterStore gets the store used for failed trigger events
+func GetDeadLetterStore() DeadLetterStore {
+	deadLetterStoreMu.RLock()
+	defer deadLetterStoreMu.RUnlock()
+
+	return deadLetterStore
+}
+
+func newDeadLetter(handlerId string, triggerData map[string]interface{}, err error, attempts int) *DeadLetter {
+
+	values := make(map[string]interface{}, len(triggerData))
+	for k, v := range triggerData {
+		if attr, ok := v.(*data.Attribute); ok {
+			values[k] = attr.Value()
+		} else {
+			values[k] = v
+		}
+	}
+
+	letter := &DeadLetter{HandlerId: handlerId, Data: values, Error: err.Error(), Reason: deadLetterReason(err), Attempts: attempts, Timestamp: time.Now()}
+
+	if deadLetterIdGen != nil {
+		letter.Id = deadLetterIdGen.NextAsString()
+	} else {
+		letter.Id = fmt.Sprintf("%d", letter.Timestamp.UnixNano())
+	}
+
+	return letter
+}
+
+// deadLetterReason gets the reason an event is dead lettered, events rejected by the limits or the
+// circuit breaker of the handler are distinguished from events whose action failed
+func deadLetterReason(err error) string {
+
+	var limitErr *LimitExceededError
+	var circuitErr *CircuitOpenError
+
+	if errors.As(err, &limitErr) || errors.As(err, &circuitErr) {
+		return DeadLetterReasonRejected
+	}
+
+	return DeadLetterReasonFailed
+}
+
+// ReplayDeadLetters replays the dead letters in the store through their original handler,
+// successfully replayed dead letters are removed from the store while the error, reason
+// and attempts of the ones that failed again are updated
+func ReplayDeadLetters(ctx context.Context, store DeadLetterStore, handlers []*Handler) (replayed int, err error) {
+
+	letters, err := store.List()
+	if err != nil {
+		return 0, err
+	}
+
+	idToHandler := make(map[string]*Handler, len(handlers))
+	for _, handler := range handlers {
+		idToHandler[handler.Id()] = handler
+	}
+
+	for _, letter := range letters {
+
+		handler, exists := idToHandler[letter.HandlerId]
+		if !exists {
+			continue
+		}
+
+		_, attempts, err := handler.handle(ctx, letter.Data)
+		if err != nil {
+			logger.Warnf("Replay of dead letter '%s' for handler '%s' failed: %s", letter.Id, letter.HandlerId, err.Error())
+
+			updated := *letter
+			updated.Error = err.Error()
+			updated.Reason = deadLetterReason(err)
+			updated.Attempts += attempts
+			updated.Replays++
+
+			if err := store.Remove(letter.Id); err != nil {
+				return replayed, err
+			}
+			if err := store.Add(&updated); err != nil {
+				return replayed, err
+			}
+			continue
+		}
+
+		if err := store.Remove(letter.Id); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+// MemoryDeadLetterStore is an in-memory DeadLetterStore
+type MemoryDeadLetterStore struct {
+	mutex   sync.Mutex
+	letters []*DeadLetter
+}
+
+// NewMemoryDeadLetterStore creates a new in-memory DeadLetterStore
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{}
+}
+
+// Add implements DeadLetterStore.Add
+func (s *MemoryDeadLetterStore) Add(letter *DeadLetter) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+// List implements DeadLetterStore.List
+func (s *MemoryDeadLetterStore) List() ([]*DeadLetter, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	letters := make([]*DeadLetter, len(s.letters))
+	copy(letters, s.letters)
+	return letters, nil
+}
+
+// Remove implements DeadLetterStore.Remove
+func (s *MemoryDeadLetterStore) Remove(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, letter := range s.letters {
+		if letter.Id == id {
+			s.letters = append(s.letters[:i], s.letters[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("dead letter '%s' not found", id)
+}
+
+// FileDeadLetterStore is a DeadLetterStore that stores the dead letters as JSON lines in a file
+type FileDeadLetterStore struct {
+	mutex    sync.Mutex
+	fileName string
+}
+
+// NewFileDeadLetterStore creates a new file based DeadLetterStore
+func NewFileDeadLetterStore(fileName string) *FileDeadLetterStore {
+	return &FileDeadLetterStore{fileName: fileName}
+}
+
+// Add implements DeadLetterStore.Add
+func (s *FileDeadLetterStore) Add(letter *DeadLetter) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// List implements DeadLetterStore.List
+func (s *FileDeadLetterStore) List() ([]*DeadLetter, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.read()
+}
+
+// Remove implements DeadLetterStore.Remove
+func (s *FileDeadLetterStore) Remove(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	letters, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	remaining := make([]*DeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		if letter.Id == id {
+			found = true
+		} else {
+			remaining = append(remaining, letter)
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("dead letter '%s' not found", id)
+	}
+
+	return s.write(remaining)
+}
+
+func (s *FileDeadLetterStore) read() ([]*DeadLetter, error) {
+
+	f, err := os.Open(s.fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var letters []*DeadLetter
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		letter := &DeadLetter{}
+		if err := json.Unmarshal(scanner.Bytes(), letter); err != nil {
+			return nil, fmt.Errorf("unable to read dead letter from '%s': %s", s.fileName, err.Error())
+		}
+		letters = append(letters, letter)
+	}
+
+	return letters, scanner.Err()
+}
+
+func (s *FileDeadLetterStore) write(letters []*DeadLetter) error {
+
+	tmpFileName := s.fileName + ".tmp"
+
+	f, err := os.Create(tmpFileName)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	for _, letter := range letters {
+		line, err := json.Marshal(letter)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFileName, s.fileName)
+}
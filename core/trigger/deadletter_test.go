@@ -0,0 +1,150 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/action"
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAction struct {
+	err error
+}
+
+func (a *testAction) Metadata() *action.Metadata    { return &action.Metadata{ID: "test"} }
+func (a *testAction) IOMetadata() *data.IOMetadata { return nil }
+
+type testRunner struct {
+	executed int
+}
+
+func (r *testRunner) Run(context context.Context, act action.Action, uri string, options interface{}) (code int, data interface{}, err error) {
+	return 0, nil, errors.New("unsupported")
+}
+
+func (r *testRunner) RunAction(ctx context.Context, act action.Action, options map[string]interface{}) (results map[string]*data.Attribute, err error) {
+	return nil, errors.New("unsupported")
+}
+
+func (r *testRunner) Execute(ctx context.Context, act action.Action, inputs map[string]*data.Attribute) (results map[string]*data.Attribute, err error) {
+	r.executed++
+	return inputs, act.(*testAction).err
+}
+
+func newTestHandler(act *testAction, runner action.Runner) *Handler {
+	cfg := &Config{Id: "test", Handlers: []*HandlerConfig{{Action: &action.Config{Ref: "test"}}}}
+	cfg.FixUp(&Metadata{})
+
+	outputMd := map[string]*data.Attribute{"value": data.NewZeroAttribute("value", data.TypeString)}
+	return NewHandler(cfg.Handlers[0], act, outputMd, nil, runner)
+}
+
+//TestDeadLetterReplay
+func TestDeadLetterReplay(t *testing.T) {
+
+	store := NewMemoryDeadLetterStore()
+	SetDeadLetterStore(store)
+	defer SetDeadLetterStore(nil)
+
+	act := &testAction{err: errors.New("failed")}
+	runner := &testRunner{}
+	handler := newTestHandler(act, runner)
+	assert.Equal(t, "test#0", handler.Id())
+
+	_, err := handler.Handle(context.Background(), map[string]interface{}{"value": "a"})
+	assert.NotNil(t, err)
+
+	letters, err := store.List()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(letters))
+	assert.Equal(t, "test#0", letters[0].HandlerId)
+	assert.Equal(t, "failed", letters[0].Error)
+	assert.Equal(t, DeadLetterReasonFailed, letters[0].Reason)
+	assert.Equal(t, 1, letters[0].Attempts)
+	assert.Equal(t, 0, letters[0].Replays)
+	assert.Equal(t, "a", letters[0].Data["value"])
+
+	// replay still failing, letter is kept and updated
+	act.err = errors.New("failed again")
+	replayed, err := ReplayDeadLetters(context.Background(), store, []*Handler{handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, replayed)
+	updated, _ := store.List()
+	assert.Equal(t, 1, len(updated))
+	assert.Equal(t, letters[0].Id, updated[0].Id)
+	assert.Equal(t, "failed again", updated[0].Error)
+	assert.Equal(t, 2, updated[0].Attempts)
+	assert.Equal(t, 1, updated[0].Replays)
+
+	act.err = nil
+	replayed, err = ReplayDeadLetters(context.Background(), store, []*Handler{handler})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, replayed)
+	letters, _ = store.List()
+	assert.Equal(t, 0, len(letters))
+	assert.Equal(t, 3, runner.executed)
+}
+
+//TestDeadLetterRejected
+func TestDeadLetterRejected(t *testing.T) {
+
+	store := NewMemoryDeadLetterStore()
+	SetDeadLetterStore(store)
+	defer SetDeadLetterStore(nil)
+
+	runner := &testRunner{}
+	handler := newTestHandler(&testAction{}, runner)
+	handler.limiter = newLimiter("test", &LimitsConfig{Rate: 1, Burst: 1, OnLimit: OnLimitReject}, &fakeClock{now: time.Now()})
+
+	_, err := handler.Handle(context.Background(), map[string]interface{}{"value": "a"})
+	assert.Nil(t, err)
+	_, err = handler.Handle(context.Background(), map[string]interface{}{"value": "b"})
+	assert.NotNil(t, err)
+
+	letters, _ := store.List()
+	assert.Equal(t, 1, len(letters))
+	assert.Equal(t, DeadLetterReasonRejected, letters[0].Reason)
+	assert.Equal(t, 0, letters[0].Attempts)
+	assert.Equal(t, 1, runner.executed)
+}
+
+//TestFileDeadLetterStore
+func TestFileDeadLetterStore(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "deadletter")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileDeadLetterStore(filepath.Join(dir, "deadletters.jsonl"))
+
+	letters, err := store.List()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(letters))
+
+	letter1 := newDeadLetter("test#0", map[string]interface{}{"value": "a"}, errors.New("failed"), 3)
+	letter2 := newDeadLetter("test#1", map[string]interface{}{"value": "b"}, errors.New("failed"), 1)
+	assert.Nil(t, store.Add(letter1))
+	assert.Nil(t, store.Add(letter2))
+
+	letters, err = store.List()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(letters))
+	assert.Equal(t, letter1.Id, letters[0].Id)
+	assert.Equal(t, 3, letters[0].Attempts)
+	assert.Equal(t, "b", letters[1].Data["value"])
+
+	assert.Nil(t, store.Remove(letter1.Id))
+	assert.NotNil(t, store.Remove(letter1.Id))
+
+	letters, err = store.List()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(letters))
+	assert.Equal(t, letter2.Id, letters[0].Id)
+}
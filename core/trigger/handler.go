@@ -5,6 +5,7 @@ import (
 	"github.com/TIBCOSoftware/flogo-lib/core/action"
 	"github.com/TIBCOSoftware/flogo-lib/core/data"
 	"github.com/TIBCOSoftware/flogo-lib/core/mapper"
+	"github.com/TIBCOSoftware/flogo-lib/core/mapper/exprmapper"
 	"github.com/TIBCOSoftware/flogo-lib/logger"
 )
 
@@ -19,12 +20,44 @@ type Handler struct {
 
 	actionInputMapper  data.Mapper
 	actionOutputMapper data.Mapper
+
+	condition    exprmapper.CompiledMapping
+	conditionErr error
+
+	retrier *retrier
+	breaker *circuitBreaker
+	limiter *limiter
+
+	middlewares []action.Middleware
 }
 
 func NewHandler(config *HandlerConfig, act action.Action, outputMd map[string]*data.Attribute, replyMd map[string]*data.Attribute, runner action.Runner) *Handler {
 	handler := &Handler{config: config, act: act, outputMd: outputMd, replyMd: replyMd, runner: runner}
 
 	if config != nil {
+		handler.retrier = newRetrier(config.Action.Retry)
+
+		if config.Condition != "" {
+			handler.condition, handler.conditionErr = compileCondition(config.Condition)
+			if handler.conditionErr != nil {
+				logger.Errorf("Invalid condition for handler of '%s': %s", config.name(), handler.conditionErr.Error())
+			}
+		}
+
+		cbConfig, err := NewCircuitBreakerConfig(config.Settings)
+		if err != nil {
+			logger.Errorf("Unable to create circuit breaker for handler of '%s': %s", config.name(), err.Error())
+		} else if cbConfig != nil {
+			handler.breaker = newCircuitBreaker(config.name(), cbConfig)
+		}
+
+		limitsConfig, err := NewLimitsConfig(config.Settings)
+		if err != nil {
+			logger.Errorf("Unable to create limiter for handler of '%s': %s", config.name(), err.Error())
+		} else if limitsConfig != nil {
+			handler.limiter = newLimiter(config.name(), limitsConfig, realClock{})
+		}
+
 		if config.Action.Mappings != nil {
 			if len(config.Action.Mappings.Input) > 0 {
 				handler.actionInputMapper = mapper.GetFactory().NewMapper(&data.MapperDef{Mappings: config.Action.Mappings.Input}, nil)
@@ -77,23 +110,93 @@ func (h *Handler) GetStringSetting(setting string) string {
 	return strVal
 }
 
+// AddMiddleware adds middlewares that are applied when executing the action of this handler
+func (h *Handler) AddMiddleware(m ...action.Middleware) {
+	h.middlewares = append(h.middlewares, m...)
+}
+
+// Id returns the id of the handler
+func (h *Handler) Id() string {
+	if h.config == nil {
+		return ""
+	}
+
+	return h.config.Id()
+}
+
 func (h *Handler) Handle(ctx context.Context, triggerData map[string]interface{}) (map[string]*data.Attribute, error) {
 
+	results, attempts, err := h.handle(ctx, triggerData)
+
+	if err != nil {
+		if store := GetDeadLetterStore(); store != nil {
+			letter := newDeadLetter(h.Id(), triggerData, err, attempts)
+			if dlErr := store.Add(letter); dlErr != nil {
+				logger.Errorf("Unable to store dead letter for handler '%s': %s", h.Id(), dlErr.Error())
+			} else {
+				logger.Debugf("Stored dead letter '%s' for handler '%s'", letter.Id, h.Id())
+			}
+		}
+	}
+
+	return results, err
+}
+
+// handle handles the trigger data, it returns the number of attempts made to execute the action
+func (h *Handler) handle(ctx context.Context, triggerData map[string]interface{}) (map[string]*data.Attribute, int, error) {
+
 	inputs, err := h.generateInputs(triggerData)
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	results, err := h.runner.Execute(ctx, h.act, inputs)
+	if h.limiter != nil {
+		if err := h.limiter.acquire(ctx); err != nil {
+			return nil, 0, err
+		}
+		defer h.limiter.release()
+	}
+
+	if h.breaker != nil {
+		if err := h.breaker.allow(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	results, attempts, err := h.execute(ctx, inputs)
+
+	if h.breaker != nil {
+		h.breaker.record(err)
+	}
 
 	if err != nil {
-		return nil, err
+		return nil, attempts, err
 	}
 
 	retValue, err := h.generateOutputs(results)
 
-	return retValue, err
+	return retValue, attempts, err
+}
+
+func (h *Handler) execute(ctx context.Context, inputs map[string]*data.Attribute) (map[string]*data.Attribute, int, error) {
+
+	ctx = action.NewContextWithMiddleware(ctx, h.middlewares...)
+
+	if h.retrier == nil {
+		results, err := h.runner.Execute(ctx, h.act, inputs)
+		return results, 1, err
+	}
+
+	results, attempts, err := h.retrier.execute(ctx, func(ctx context.Context) (map[string]*data.Attribute, error) {
+		return h.runner.Execute(ctx, h.act, inputs)
+	})
+
+	if err != nil && attempts > 1 {
+		logger.Warnf("Action '%s' failed after %d attempts", action.GetMetadata(h.act).ID, attempts)
+	}
+
+	return results, attempts, err
 }
 
 func (h *Handler) dataToAttrs(triggerData map[string]interface{}) ([]*data.Attribute, error) {
@@ -153,7 +256,7 @@ func (h *Handler) generateInputs(triggerData map[string]interface{}) (map[string
 
 		inputMetadata := h.act.IOMetadata().Input
 
-		inScope := data.NewSimpleScope(triggerAttrs, nil)
+		inScope := data.NewImmutableScope(triggerAttrs, nil)
 		outScope := data.NewFixedScope(inputMetadata)
 
 		err := h.actionInputMapper.Apply(inScope, outScope)
@@ -161,6 +264,10 @@ func (h *Handler) generateInputs(triggerData map[string]interface{}) (map[string
 			return nil, err
 		}
 
+		if err := outScope.Validate(); err != nil {
+			return nil, err
+		}
+
 		attrs := outScope.GetAttrs()
 
 		inputs = make(map[string]*data.Attribute, len(inputMetadata))
@@ -205,7 +312,7 @@ func (h *Handler) generateOutputs(actionResults map[string]*data.Attribute) (map
 	if outputMetadata != nil {
 
 		outScope := data.NewFixedScopeFromMap(h.replyMd)
-		inScope := data.NewSimpleScopeFromMap(actionResults, nil)
+		inScope := data.NewImmutableScopeFromMap(actionResults, nil)
 
 		err := h.actionOutputMapper.Apply(inScope, outScope)
 		if err != nil {
@@ -0,0 +1,237 @@
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+)
+
+// SettingLimits is the handler setting used to configure the rate and concurrency limits
+const SettingLimits = "limits"
+
+const (
+	// OnLimitWait waits until the action can be executed
+	OnLimitWait = "wait"
+	// OnLimitReject rejects the execution with a LimitExceededError
+	OnLimitReject = "reject"
+)
+
+// LimitsConfig is the configuration of the handler limits
+type LimitsConfig struct {
+	// Rate is the number of executions allowed per second, 0 means unlimited
+	Rate float64
+	// Burst is the maximum number of executions allowed at once, 0 defaults to 1
+	Burst int
+	// MaxConcurrency is the maximum number of concurrent executions, 0 means unlimited
+	MaxConcurrency int
+	// OnLimit is the behaviour when a limit is reached, either "wait" (default) or "reject"
+	OnLimit string
+}
+
+// LimitExceededError is returned when an execution is rejected because a limit was reached
+type LimitExceededError struct {
+	name  string
+	limit string
+}
+
+// Error implements error.Error()
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s limit exceeded for '%s'", e.limit, e.name)
+}
+
+// Name the name of the limited handler
+func (e *LimitExceededError) Name() string {
+	return e.name
+}
+
+// Limit the limit that was exceeded, either "rate" or "concurrency"
+func (e *LimitExceededError) Limit() string {
+	return e.limit
+}
+
+// NewLimitsConfig creates a LimitsConfig from the handler settings,
+// returns nil if no limits have been configured
+func NewLimitsConfig(settings map[string]interface{}) (*LimitsConfig, error) {
+
+	val, exists := settings[SettingLimits]
+	if !exists || val == nil {
+		return nil, nil
+	}
+
+	values, err := data.CoerceToObject(val)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' setting: %s", SettingLimits, err.Error())
+	}
+
+	config := &LimitsConfig{Burst: 1, OnLimit: OnLimitWait}
+
+	for key, value := range values {
+		switch key {
+		case "rate":
+			config.Rate, err = data.CoerceToNumber(value)
+		case "burst":
+			config.Burst, err = data.CoerceToInteger(value)
+		case "maxConcurrency":
+			config.MaxConcurrency, err = data.CoerceToInteger(value)
+		case "onLimit":
+			config.OnLimit, err = data.CoerceToString(value)
+			if err == nil && config.OnLimit != OnLimitWait && config.OnLimit != OnLimitReject {
+				err = fmt.Errorf("unsupported 'onLimit' value '%s'", config.OnLimit)
+			}
+		default:
+			err = fmt.Errorf("unknown setting '%s'", key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid '%s' setting: %s", SettingLimits, err.Error())
+		}
+	}
+
+	if config.Rate < 0 {
+		return nil, fmt.Errorf("invalid '%s' setting: rate must be >= 0", SettingLimits)
+	}
+
+	if config.Burst < 0 {
+		return nil, fmt.Errorf("invalid '%s' setting: burst must be >= 0", SettingLimits)
+	}
+
+	if config.MaxConcurrency < 0 {
+		return nil, fmt.Errorf("invalid '%s' setting: maxConcurrency must be >= 0", SettingLimits)
+	}
+
+	if config.Burst == 0 {
+		config.Burst = 1
+	}
+
+	return config, nil
+}
+
+// clock is used to abstract time, so it can be faked in tests
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// limiter enforces the rate and concurrency limits of a handler
+type limiter struct {
+	name   string
+	config *LimitsConfig
+	clock  clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	slots chan struct{}
+}
+
+func newLimiter(name string, config *LimitsConfig, clk clock) *limiter {
+
+	l := &limiter{name: name, config: config, clock: clk, tokens: float64(config.Burst), last: clk.Now()}
+
+	if config.MaxConcurrency > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrency)
+	}
+
+	return l
+}
+
+// acquire acquires permission to execute, release must be called
+// once the execution is done if no error is returned
+func (l *limiter) acquire(ctx context.Context) error {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if l.config.Rate > 0 {
+		if err := l.takeToken(ctx); err != nil {
+			return err
+		}
+	}
+
+	if l.slots != nil {
+		if l.config.OnLimit == OnLimitReject {
+			select {
+			case l.slots <- struct{}{}:
+			default:
+				l.refundToken()
+				return &LimitExceededError{name: l.name, limit: "concurrency"}
+			}
+		} else {
+			select {
+			case l.slots <- struct{}{}:
+			case <-ctx.Done():
+				l.refundToken()
+				return ctx.Err()
+			}
+		}
+	}
+
+	return nil
+}
+
+// refundToken gives back the rate token taken when the execution
+// didn't get a concurrency slot, so the rate isn't throttled by it
+func (l *limiter) refundToken() {
+
+	if l.config.Rate <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+// release releases the concurrency slot acquired
+func (l *limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+func (l *limiter) takeToken(ctx context.Context) error {
+
+	l.mu.Lock()
+
+	now := l.clock.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.config.Rate
+	if l.tokens > float64(l.config.Burst) {
+		l.tokens = float64(l.config.Burst)
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+
+	if l.config.OnLimit == OnLimitReject {
+		l.mu.Unlock()
+		return &LimitExceededError{name: l.name, limit: "rate"}
+	}
+
+	// reserve the token and wait until it is available
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.config.Rate * float64(time.Second))
+	l.mu.Unlock()
+
+	select {
+	case <-l.clock.After(wait):
+		return nil
+	case <-ctx.Done():
+		// give back the reservation
+		l.refundToken()
+		return ctx.Err()
+	}
+}
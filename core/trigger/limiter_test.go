@@ -0,0 +1,224 @@
+package trigger
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	return t.c
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+
+	var pending []*fakeTimer
+	for _, t := range c.timers {
+		if !t.at.After(c.now) {
+			t.c <- c.now
+		} else {
+			pending = append(pending, t)
+		}
+	}
+	c.timers = pending
+}
+
+func (c *fakeClock) Waiting() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+//TestNewLimitsConfig
+func TestNewLimitsConfig(t *testing.T) {
+
+	config, err := NewLimitsConfig(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Nil(t, config)
+
+	config, err = NewLimitsConfig(map[string]interface{}{
+		"limits": map[string]interface{}{"rate": 10.0, "maxConcurrency": 2.0, "onLimit": "reject"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 10.0, config.Rate)
+	assert.Equal(t, 1, config.Burst)
+	assert.Equal(t, 2, config.MaxConcurrency)
+	assert.Equal(t, OnLimitReject, config.OnLimit)
+
+	_, err = NewLimitsConfig(map[string]interface{}{
+		"limits": map[string]interface{}{"onLimit": "drop"},
+	})
+	assert.NotNil(t, err)
+
+	_, err = NewLimitsConfig(map[string]interface{}{"limits": map[string]interface{}{"rate": -10.0}})
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid 'limits' setting: rate must be >= 0", err.Error())
+
+	_, err = NewLimitsConfig(map[string]interface{}{"limits": map[string]interface{}{"rate": 10.0, "burst": -1}})
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid 'limits' setting: burst must be >= 0", err.Error())
+
+	_, err = NewLimitsConfig(map[string]interface{}{"limits": map[string]interface{}{"maxConcurrency": -2}})
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid 'limits' setting: maxConcurrency must be >= 0", err.Error())
+}
+
+//TestLimiterRateReject
+func TestLimiterRateReject(t *testing.T) {
+
+	clk := &fakeClock{now: time.Now()}
+	l := newLimiter("test", &LimitsConfig{Rate: 2, Burst: 2, OnLimit: OnLimitReject}, clk)
+
+	assert.Nil(t, l.acquire(nil))
+	assert.Nil(t, l.acquire(nil))
+
+	err := l.acquire(nil)
+	assert.NotNil(t, err)
+	limitErr, ok := err.(*LimitExceededError)
+	assert.True(t, ok)
+	assert.Equal(t, "rate", limitErr.Limit())
+
+	// one token is added every 500ms
+	clk.Advance(500 * time.Millisecond)
+	assert.Nil(t, l.acquire(nil))
+	assert.NotNil(t, l.acquire(nil))
+}
+
+//TestLimiterRateWait
+func TestLimiterRateWait(t *testing.T) {
+
+	clk := &fakeClock{now: time.Now()}
+	l := newLimiter("test", &LimitsConfig{Rate: 1, Burst: 1, OnLimit: OnLimitWait}, clk)
+
+	assert.Nil(t, l.acquire(nil))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.acquire(nil)
+	}()
+
+	for clk.Waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-done:
+		t.Fatal("acquire should wait for the next token")
+	default:
+	}
+
+	clk.Advance(time.Second)
+	assert.Nil(t, <-done)
+}
+
+//TestLimiterRateWaitCancelled
+func TestLimiterRateWaitCancelled(t *testing.T) {
+
+	clk := &fakeClock{now: time.Now()}
+	l := newLimiter("test", &LimitsConfig{Rate: 1, Burst: 1, OnLimit: OnLimitWait}, clk)
+
+	assert.Nil(t, l.acquire(nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, context.Canceled, l.acquire(ctx))
+}
+
+//TestLimiterConcurrency
+func TestLimiterConcurrency(t *testing.T) {
+
+	clk := &fakeClock{now: time.Now()}
+	l := newLimiter("test", &LimitsConfig{MaxConcurrency: 1, OnLimit: OnLimitReject}, clk)
+
+	assert.Nil(t, l.acquire(nil))
+
+	err := l.acquire(nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "concurrency", err.(*LimitExceededError).Limit())
+
+	l.release()
+	assert.Nil(t, l.acquire(nil))
+
+	l = newLimiter("test", &LimitsConfig{MaxConcurrency: 1, OnLimit: OnLimitWait}, clk)
+	assert.Nil(t, l.acquire(nil))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.acquire(nil)
+	}()
+
+	l.release()
+	assert.Nil(t, <-done)
+}
+
+//TestLimiterRefundsToken
+func TestLimiterRefundsToken(t *testing.T) {
+
+	clk := &fakeClock{now: time.Now()}
+	l := newLimiter("test", &LimitsConfig{Rate: 1, Burst: 2, MaxConcurrency: 1, OnLimit: OnLimitReject}, clk)
+
+	assert.Nil(t, l.acquire(nil))
+
+	// rejected for concurrency, the rate token has to be given back
+	err := l.acquire(nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "concurrency", err.(*LimitExceededError).Limit())
+
+	l.release()
+	assert.Nil(t, l.acquire(nil))
+	l.release()
+
+	assert.Equal(t, "rate", l.acquire(nil).(*LimitExceededError).Limit())
+
+	l = newLimiter("test", &LimitsConfig{Rate: 1, Burst: 2, MaxConcurrency: 1, OnLimit: OnLimitWait}, clk)
+	assert.Nil(t, l.acquire(nil))
+
+	// cancelled while waiting for a concurrency slot, the rate token has to be given back
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, l.acquire(ctx))
+
+	l.release()
+	assert.Nil(t, l.acquire(nil))
+	l.release()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.acquire(nil)
+	}()
+
+	for clk.Waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	clk.Advance(time.Second)
+	assert.Nil(t, <-done)
+}
@@ -2,13 +2,17 @@ package trigger
 
 import (
 	"fmt"
+	"sync"
 )
 
 var (
-	triggerFactories = make(map[string]Factory)
+	triggerFactoriesMu sync.RWMutex
+	triggerFactories   = make(map[string]Factory)
 )
 
 func RegisterFactory(ref string, f Factory) error {
+	triggerFactoriesMu.Lock()
+	defer triggerFactoriesMu.Unlock()
 
 	if len(ref) == 0 {
 		return fmt.Errorf("'ref' must be specified when registering a trigger factory")
@@ -28,10 +32,21 @@ func RegisterFactory(ref string, f Factory) error {
 }
 
 func GetFactory(ref string) Factory {
+	triggerFactoriesMu.RLock()
+	defer triggerFactoriesMu.RUnlock()
+
 	return triggerFactories[ref]
 }
 
 func Factories() map[string]Factory {
-	//todo return copy
-	return triggerFactories
+	triggerFactoriesMu.RLock()
+	defer triggerFactoriesMu.RUnlock()
+
+	factoriesCopy := make(map[string]Factory, len(triggerFactories))
+
+	for ref, factory := range triggerFactories {
+		factoriesCopy[ref] = factory
+	}
+
+	return factoriesCopy
 }
@@ -0,0 +1,114 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/action"
+	"github.com/TIBCOSoftware/flogo-lib/core/activity"
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/TIBCOSoftware/flogo-lib/logger"
+)
+
+type executeFunc func(ctx context.Context) (map[string]*data.Attribute, error)
+
+// retrier executes an action according to its retry configuration
+type retrier struct {
+	config  *action.RetryConfig
+	retryOn map[string]bool
+
+	random func() float64
+}
+
+func newRetrier(config *action.RetryConfig) *retrier {
+
+	if config == nil || config.MaxAttempts <= 1 {
+		return nil
+	}
+
+	r := &retrier{config: config, random: rand.Float64}
+
+	if len(config.RetryOn) > 0 {
+		r.retryOn = make(map[string]bool, len(config.RetryOn))
+		for _, code := range config.RetryOn {
+			r.retryOn[code] = true
+		}
+	}
+
+	return r
+}
+
+// execute runs the function until it succeeds, the error is not retryable or
+// the max attempts are reached, it returns the number of attempts made
+func (r *retrier) execute(ctx context.Context, execute executeFunc) (map[string]*data.Attribute, int, error) {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for attempt := 1; ; attempt++ {
+
+		results, err := execute(action.NewContextWithAttempt(ctx, attempt))
+
+		if err == nil || attempt >= r.config.MaxAttempts || !r.retryable(err) {
+			return results, attempt, err
+		}
+
+		delay := r.delay(attempt)
+		logger.Debugf("Attempt %d failed with error '%s', retrying in %v", attempt, err.Error(), delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, attempt, err
+		}
+	}
+}
+
+// retryable determines if the error should be retried, activity errors are
+// looked up through the error chain so wrapped errors keep their code
+func (r *retrier) retryable(err error) bool {
+
+	if r.retryOn == nil {
+		return true
+	}
+
+	var actErr *activity.Error
+	if errors.As(err, &actErr) {
+		return r.retryOn[actErr.Code()]
+	}
+
+	return false
+}
+
+// delay calculates the delay before the next attempt
+func (r *retrier) delay(attempt int) time.Duration {
+
+	interval := float64(r.config.Interval)
+
+	if r.config.Backoff == action.BackoffExponential {
+		for i := 1; i < attempt; i++ {
+			interval *= 2
+			if r.config.MaxInterval > 0 && interval >= float64(r.config.MaxInterval) {
+				break
+			}
+		}
+	}
+
+	if r.config.MaxInterval > 0 && interval > float64(r.config.MaxInterval) {
+		interval = float64(r.config.MaxInterval)
+	}
+
+	if r.config.Jitter > 0 {
+		jitter := r.config.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		// randomize within [interval*(1-jitter), interval*(1+jitter)]
+		interval += interval * jitter * (2*r.random() - 1)
+	}
+
+	return time.Duration(interval) * time.Millisecond
+}
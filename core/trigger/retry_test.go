@@ -0,0 +1,84 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/action"
+	"github.com/TIBCOSoftware/flogo-lib/core/activity"
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/stretchr/testify/assert"
+)
+
+//TestNewRetrierDisabled
+func TestNewRetrierDisabled(t *testing.T) {
+
+	assert.Nil(t, newRetrier(nil))
+	assert.Nil(t, newRetrier(&action.RetryConfig{MaxAttempts: 1}))
+	assert.NotNil(t, newRetrier(&action.RetryConfig{MaxAttempts: 2}))
+}
+
+//TestRetrierDelay
+func TestRetrierDelay(t *testing.T) {
+
+	r := newRetrier(&action.RetryConfig{MaxAttempts: 5, Interval: 100})
+	assert.Equal(t, 100*time.Millisecond, r.delay(1))
+	assert.Equal(t, 100*time.Millisecond, r.delay(3))
+
+	r = newRetrier(&action.RetryConfig{MaxAttempts: 5, Interval: 100, Backoff: action.BackoffExponential, MaxInterval: 300})
+	assert.Equal(t, 100*time.Millisecond, r.delay(1))
+	assert.Equal(t, 200*time.Millisecond, r.delay(2))
+	assert.Equal(t, 300*time.Millisecond, r.delay(3))
+
+	r = newRetrier(&action.RetryConfig{MaxAttempts: 5, Interval: 100, Jitter: 0.5})
+	r.random = func() float64 { return 1 }
+	assert.Equal(t, 150*time.Millisecond, r.delay(1))
+	r.random = func() float64 { return 0 }
+	assert.Equal(t, 50*time.Millisecond, r.delay(1))
+}
+
+//TestRetrierRetryOn
+func TestRetrierRetryOn(t *testing.T) {
+
+	r := newRetrier(&action.RetryConfig{MaxAttempts: 2})
+	assert.True(t, r.retryable(errors.New("any")))
+
+	r = newRetrier(&action.RetryConfig{MaxAttempts: 2, RetryOn: []string{"TIMEOUT"}})
+	assert.True(t, r.retryable(activity.NewError("timeout", "TIMEOUT", nil)))
+	assert.False(t, r.retryable(activity.NewError("invalid", "INVALID", nil)))
+	assert.False(t, r.retryable(errors.New("any")))
+
+	wrapped := fmt.Errorf("flow failed: %w", activity.NewError("timeout", "TIMEOUT", nil))
+	assert.True(t, r.retryable(wrapped))
+	assert.False(t, r.retryable(fmt.Errorf("flow failed: %w", activity.NewError("invalid", "INVALID", nil))))
+}
+
+//TestRetrierExecute
+func TestRetrierExecute(t *testing.T) {
+
+	r := newRetrier(&action.RetryConfig{MaxAttempts: 3})
+
+	var seen []int
+	_, attempts, err := r.execute(context.Background(), func(ctx context.Context) (map[string]*data.Attribute, error) {
+		attempt, _ := action.GetAttempt(ctx)
+		seen = append(seen, attempt)
+		if attempt < 2 {
+			return nil, errors.New("failed")
+		}
+		return nil, nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []int{1, 2}, seen)
+
+	_, attempts, err = r.execute(context.Background(), func(ctx context.Context) (map[string]*data.Attribute, error) {
+		return nil, errors.New("failed")
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, attempts)
+}
@@ -0,0 +1,111 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/TIBCOSoftware/flogo-lib/core/mapper/exprmapper"
+	"github.com/TIBCOSoftware/flogo-lib/core/mapper/exprmapper/expression"
+)
+
+// DispatchMode determines to which of the matching handlers an event is dispatched
+type DispatchMode int
+
+const (
+	// DispatchFirst dispatches the event to the first matching handler
+	DispatchFirst DispatchMode = iota
+
+	// DispatchAll dispatches the event to all the matching handlers
+	DispatchAll
+)
+
+// ErrNoMatchingHandler is returned by Dispatch when no handler matches the event
+var ErrNoMatchingHandler = errors.New("no handler matched the trigger data")
+
+// HandlerResult is the result of a handler an event was dispatched to
+type HandlerResult struct {
+	Handler *Handler
+	Results map[string]*data.Attribute
+	Err     error
+}
+
+// Matches determines if the handler should handle the trigger data, a handler without a
+// condition matches all trigger data
+func (h *Handler) Matches(triggerData map[string]interface{}) (bool, error) {
+
+	if h.config == nil || h.config.Condition == "" {
+		return true, nil
+	}
+
+	if h.conditionErr != nil {
+		return false, fmt.Errorf("invalid condition of handler '%s': %s", h.config.name(), h.conditionErr.Error())
+	}
+
+	attrs, err := h.dataToAttrs(triggerData)
+	if err != nil {
+		return false, err
+	}
+
+	value, err := h.condition(data.NewImmutableScope(attrs, nil), data.GetBasicResolver())
+	if err != nil {
+		return false, fmt.Errorf("unable to evaluate condition of handler '%s': %s", h.config.name(), err.Error())
+	}
+
+	matches, err := data.CoerceToBoolean(value)
+	if err != nil {
+		return false, fmt.Errorf("condition of handler '%s' is not a boolean: %s", h.config.name(), err.Error())
+	}
+
+	return matches, nil
+}
+
+// compileCondition parses the condition of a handler, so it doesn't have to be parsed for every event.
+// Unlike a mapping, a condition that can't be parsed isn't treated as a literal value.
+func compileCondition(condition string) (exprmapper.CompiledMapping, error) {
+
+	if _, err := strconv.ParseBool(condition); err == nil {
+		return exprmapper.CompileMappingValue(condition)
+	}
+
+	if _, err := expression.GetParser(condition); err != nil {
+		return nil, fmt.Errorf("unable to parse condition [%s] - %s", condition, err.Error())
+	}
+
+	return exprmapper.CompileMappingValue(condition)
+}
+
+// Dispatch dispatches the trigger data to the handlers whose condition matches it, in the order of the
+// handlers. ErrNoMatchingHandler is returned when none of the handlers match, errors of the handlers
+// themselves are returned in their HandlerResult.
+func Dispatch(ctx context.Context, handlers []*Handler, triggerData map[string]interface{}, mode DispatchMode) ([]*HandlerResult, error) {
+
+	var results []*HandlerResult
+
+	for _, handler := range handlers {
+
+		matches, err := handler.Matches(triggerData)
+		if err != nil {
+			return results, err
+		}
+
+		if !matches {
+			continue
+		}
+
+		handlerResults, err := handler.Handle(ctx, triggerData)
+		results = append(results, &HandlerResult{Handler: handler, Results: handlerResults, Err: err})
+
+		if mode == DispatchFirst {
+			break
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNoMatchingHandler
+	}
+
+	return results, nil
+}
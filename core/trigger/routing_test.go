@@ -0,0 +1,121 @@
+package trigger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/action"
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRoutingHandlers(runner action.Runner, conditions ...string) []*Handler {
+
+	cfg := &Config{Id: "routing"}
+	for _, condition := range conditions {
+		cfg.Handlers = append(cfg.Handlers, &HandlerConfig{Action: &action.Config{Ref: "test"}, Condition: condition})
+	}
+	cfg.FixUp(&Metadata{})
+
+	outputMd := map[string]*data.Attribute{
+		"method": data.NewZeroAttribute("method", data.TypeString),
+		"amount": data.NewZeroAttribute("amount", data.TypeInteger),
+	}
+
+	var handlers []*Handler
+	for _, hc := range cfg.Handlers {
+		handlers = append(handlers, NewHandler(hc, &testAction{}, outputMd, nil, runner))
+	}
+
+	return handlers
+}
+
+//TestHandlerMatches
+func TestHandlerMatches(t *testing.T) {
+
+	handlers := newRoutingHandlers(&testRunner{}, `$trigger.method == "GET"`, `$trigger.amount > 100`, "")
+	triggerData := map[string]interface{}{"method": "GET", "amount": 50}
+
+	matches, err := handlers[0].Matches(triggerData)
+	assert.Nil(t, err)
+	assert.True(t, matches)
+
+	matches, err = handlers[1].Matches(triggerData)
+	assert.Nil(t, err)
+	assert.False(t, matches)
+
+	matches, err = handlers[2].Matches(triggerData)
+	assert.Nil(t, err)
+	assert.True(t, matches)
+}
+
+//TestHandlerInvalidCondition
+func TestHandlerInvalidCondition(t *testing.T) {
+
+	handlers := newRoutingHandlers(&testRunner{}, `$trigger.method == `, "true")
+
+	_, err := handlers[0].Matches(map[string]interface{}{"method": "GET"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid condition of handler")
+
+	matches, err := handlers[1].Matches(map[string]interface{}{"method": "GET"})
+	assert.Nil(t, err)
+	assert.True(t, matches)
+
+	md := NewMetadata(validateMetadata)
+
+	cfg := &Config{Id: "rest", Settings: map[string]interface{}{"port": 8080}, Handlers: []*HandlerConfig{
+		{Settings: map[string]interface{}{"method": "GET"}, Condition: `$trigger.count > 1`},
+		{Settings: map[string]interface{}{"method": "GET"}, Condition: `$trigger.count >`},
+	}}
+	err = cfg.Validate(md)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "trigger 'rest': handler 1: invalid condition: unable to parse condition [$trigger.count >]")
+}
+
+//TestDispatchFirst
+func TestDispatchFirst(t *testing.T) {
+
+	runner := &testRunner{}
+	handlers := newRoutingHandlers(runner, `$trigger.method == "POST"`, `$trigger.method == "GET"`, "")
+
+	results, err := Dispatch(context.Background(), handlers, map[string]interface{}{"method": "GET"}, DispatchFirst)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, handlers[1], results[0].Handler)
+	assert.Equal(t, 1, runner.executed)
+}
+
+//TestDispatchAll
+func TestDispatchAll(t *testing.T) {
+
+	runner := &testRunner{}
+	handlers := newRoutingHandlers(runner, `$trigger.method == "GET"`, `$trigger.amount > 100`, `$trigger.amount > 1000`, "")
+
+	results, err := Dispatch(context.Background(), handlers, map[string]interface{}{"method": "GET", "amount": 500}, DispatchAll)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, handlers[0], results[0].Handler)
+	assert.Equal(t, handlers[1], results[1].Handler)
+	assert.Equal(t, handlers[3], results[2].Handler)
+	assert.Equal(t, 3, runner.executed)
+
+	for _, result := range results {
+		assert.Nil(t, result.Err)
+	}
+}
+
+//TestDispatchNoMatch
+func TestDispatchNoMatch(t *testing.T) {
+
+	runner := &testRunner{}
+	handlers := newRoutingHandlers(runner, `$trigger.method == "POST"`, `$trigger.amount > 100`)
+
+	results, err := Dispatch(context.Background(), handlers, map[string]interface{}{"method": "GET", "amount": 50}, DispatchAll)
+	assert.Equal(t, ErrNoMatchingHandler, err)
+	assert.Nil(t, results)
+	assert.Equal(t, 0, runner.executed)
+
+	_, err = Dispatch(context.Background(), nil, map[string]interface{}{"method": "GET"}, DispatchFirst)
+	assert.Equal(t, ErrNoMatchingHandler, err)
+}
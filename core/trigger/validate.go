@@ -0,0 +1,121 @@
+package trigger
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/TIBCOSoftware/flogo-lib/core/data"
+)
+
+// engineHandlerSettings are the handler settings that are handled by the engine instead of the trigger
+var engineHandlerSettings = map[string]bool{
+	SettingCircuitBreaker: true,
+	SettingLimits:         true,
+}
+
+// Validate validates the settings of the trigger and its handlers against the metadata, settings
+// have to be defined in the metadata, required settings have to be specified and the values have
+// to be coercible to the type of the setting and satisfy its constraints
+func (c *Config) Validate(metadata *Metadata) error {
+
+	if err := validateSettings(c.Settings, metadata.Settings, nil); err != nil {
+		return fmt.Errorf("trigger '%s': %s", c.Id, err.Error())
+	}
+
+	handlerSettings := make(map[string]*data.Attribute)
+
+	if metadata.Handler != nil {
+		for _, attr := range metadata.Handler.Settings {
+			handlerSettings[attr.Name()] = attr
+		}
+	}
+
+	for i, hc := range c.Handlers {
+		if err := validateSettings(hc.Settings, handlerSettings, engineHandlerSettings); err != nil {
+			return fmt.Errorf("trigger '%s': handler %d: %s", c.Id, i, err.Error())
+		}
+
+		if err := validateEngineSettings(hc); err != nil {
+			return fmt.Errorf("trigger '%s': handler %d: %s", c.Id, i, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateEngineSettings validates the handler settings and condition that are handled by the engine, so
+// that an invalid configuration fails at startup instead of the handler silently running without it
+func validateEngineSettings(hc *HandlerConfig) error {
+
+	if _, err := NewCircuitBreakerConfig(hc.Settings); err != nil {
+		return err
+	}
+
+	if _, err := NewLimitsConfig(hc.Settings); err != nil {
+		return err
+	}
+
+	if hc.Condition != "" {
+		if _, err := compileCondition(hc.Condition); err != nil {
+			return fmt.Errorf("invalid condition: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func validateSettings(settings map[string]interface{}, mdSettings map[string]*data.Attribute, allowed map[string]bool) error {
+
+	var names []string
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+
+		if allowed[name] {
+			continue
+		}
+
+		attr, defined := mdSettings[name]
+		if !defined {
+			return fmt.Errorf("unknown setting '%s'", name)
+		}
+
+		value, _ := data.GetValueWithResolver(settings, name)
+
+		if strVal, ok := value.(string); ok && len(strVal) > 0 && strVal[0] == '$' {
+			// value could not be resolved yet, so it can't be validated
+			continue
+		}
+
+		coerced, err := data.CoerceToValue(value, attr.Type())
+		if err != nil {
+			return fmt.Errorf("invalid setting '%s', expected %s - %s", name, attr.Type().String(), err.Error())
+		}
+
+		if err := attr.Metadata().Validate(coerced); err != nil {
+			return fmt.Errorf("invalid setting '%s' - %s", name, err.Error())
+		}
+	}
+
+	var mdNames []string
+	for name := range mdSettings {
+		mdNames = append(mdNames, name)
+	}
+	sort.Strings(mdNames)
+
+	for _, name := range mdNames {
+		md := mdSettings[name].Metadata()
+		if md == nil || !md.Required {
+			continue
+		}
+
+		if value, exists := settings[name]; !exists || value == nil || value == "" {
+			return fmt.Errorf("missing required setting '%s'", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package trigger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const validateMetadata = `{
+  "name": "test-trigger",
+  "ref": "github.com/flogo/trigger/test",
+  "settings": [
+    { "name": "port", "type": "integer", "required": true },
+    { "name": "host", "type": "string" }
+  ],
+  "output": [
+    { "name": "count", "type": "integer" }
+  ],
+  "handler": {
+    "settings": [
+      { "name": "method", "type": "string", "required": true, "allowed": ["GET", "POST"] },
+      { "name": "secure", "type": "boolean" }
+    ]
+  }
+}`
+
+//TestValidateSettings
+func TestValidateSettings(t *testing.T) {
+
+	md := NewMetadata(validateMetadata)
+
+	cfg := &Config{Id: "rest", Settings: map[string]interface{}{"port": "8080"}, Handlers: []*HandlerConfig{
+		{Settings: map[string]interface{}{"method": "GET", "secure": "true", SettingLimits: map[string]interface{}{"rate": 10}}},
+	}}
+	assert.Nil(t, cfg.Validate(md))
+
+	cfg = &Config{Id: "rest", Settings: map[string]interface{}{"port": "abc"}}
+	err := cfg.Validate(md)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "trigger 'rest': invalid setting 'port', expected integer")
+
+	cfg = &Config{Id: "rest", Settings: map[string]interface{}{"port": 8080, "unknown": "value"}}
+	err = cfg.Validate(md)
+	assert.NotNil(t, err)
+	assert.Equal(t, "trigger 'rest': unknown setting 'unknown'", err.Error())
+
+	cfg = &Config{Id: "rest", Settings: map[string]interface{}{"host": "localhost"}}
+	err = cfg.Validate(md)
+	assert.NotNil(t, err)
+	assert.Equal(t, "trigger 'rest': missing required setting 'port'", err.Error())
+
+	cfg = &Config{Id: "rest", Settings: map[string]interface{}{"port": 8080}, Handlers: []*HandlerConfig{
+		{Settings: map[string]interface{}{"method": "GET"}},
+		{Settings: map[string]interface{}{"secure": true}},
+	}}
+	err = cfg.Validate(md)
+	assert.NotNil(t, err)
+	assert.Equal(t, "trigger 'rest': handler 1: missing required setting 'method'", err.Error())
+
+	cfg = &Config{Id: "rest", Settings: map[string]interface{}{"port": 8080}, Handlers: []*HandlerConfig{
+		{Settings: map[string]interface{}{"method": "PATCH"}},
+	}}
+	err = cfg.Validate(md)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "trigger 'rest': handler 0: invalid setting 'method' - value 'PATCH' is not one of the allowed values")
+}
+
+//TestValidateCircuitBreakerSetting
+func TestValidateCircuitBreakerSetting(t *testing.T) {
+
+	md := NewMetadata(validateMetadata)
+
+	cfg := &Config{Id: "rest", Settings: map[string]interface{}{"port": 8080}, Handlers: []*HandlerConfig{
+		{Settings: map[string]interface{}{"method": "GET", SettingCircuitBreaker: map[string]interface{}{"consecutiveFailures": 3}}},
+	}}
+	assert.Nil(t, cfg.Validate(md))
+
+	cfg = &Config{Id: "rest", Settings: map[string]interface{}{"port": 8080}, Handlers: []*HandlerConfig{
+		{Settings: map[string]interface{}{"method": "GET", SettingCircuitBreaker: map[string]interface{}{"window": 5}}},
+	}}
+	err := cfg.Validate(md)
+	assert.NotNil(t, err)
+	assert.Equal(t, "trigger 'rest': handler 0: invalid 'circuitBreaker' setting: 'consecutiveFailures' or 'failureRate' must be specified", err.Error())
+}
+
+//TestValidateLimitsSetting
+func TestValidateLimitsSetting(t *testing.T) {
+
+	md := NewMetadata(validateMetadata)
+
+	cfg := &Config{Id: "rest", Settings: map[string]interface{}{"port": 8080}, Handlers: []*HandlerConfig{
+		{Settings: map[string]interface{}{"method": "GET", SettingLimits: map[string]interface{}{"rate": 5, "onLimit": "drop"}}},
+	}}
+	err := cfg.Validate(md)
+	assert.NotNil(t, err)
+	assert.Equal(t, "trigger 'rest': handler 0: invalid 'limits' setting: unsupported 'onLimit' value 'drop'", err.Error())
+}
+
+//TestValidateUnresolvedSetting
+func TestValidateUnresolvedSetting(t *testing.T) {
+
+	md := NewMetadata(validateMetadata)
+
+	cfg := &Config{Id: "rest", Settings: map[string]interface{}{"port": "$unknown.port"}}
+	assert.Nil(t, cfg.Validate(md))
+}
+
+//TestFixUpInvalidOutput
+func TestFixUpInvalidOutput(t *testing.T) {
+
+	md := NewMetadata(validateMetadata)
+
+	cfg := &Config{Id: "rest", Output: map[string]interface{}{"count": "1"}}
+	assert.Nil(t, cfg.FixUp(md))
+	assert.Equal(t, 1, cfg.Output["count"])
+
+	cfg = &Config{Id: "rest", Output: map[string]interface{}{"count": "abc"}}
+	err := cfg.FixUp(md)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "trigger 'rest': invalid output 'count'")
+}
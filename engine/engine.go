@@ -1,12 +1,15 @@
 package engine
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"runtime/debug"
+	"sort"
 
 	"github.com/TIBCOSoftware/flogo-lib/app"
+	"github.com/TIBCOSoftware/flogo-lib/app/resource"
 	"github.com/TIBCOSoftware/flogo-lib/config"
 	"github.com/TIBCOSoftware/flogo-lib/core/action"
 	"github.com/TIBCOSoftware/flogo-lib/core/data"
@@ -32,6 +35,7 @@ type EngineConfig struct {
 	serviceManager *util.ServiceManager
 
 	triggers map[string]trigger.Trigger
+	handlers []*trigger.Handler
 }
 
 // New creates a new Engine
@@ -60,7 +64,6 @@ func New(appCfg *app.Config) (Engine, error) {
 func (e *EngineConfig) Init(directRunner bool) error {
 
 	if !e.initialized {
-		e.initialized = true
 
 		if directRunner {
 			e.actionRunner = runner.NewDirect()
@@ -69,13 +72,42 @@ func (e *EngineConfig) Init(directRunner bool) error {
 			e.actionRunner = runner.NewPooled(runnerConfig.Pooled)
 		}
 
+		properties, err := app.OverrideProperties(e.App.Properties)
+		if err != nil {
+			return err
+		}
+
+		properties, err = app.ValidateProperties(e.App.PropertyDefs, properties)
+		if err != nil {
+			return fmt.Errorf("Engine: Invalid app properties - %s", err.Error())
+		}
+
+		if unused, err := app.UnusedProperties(e.App, properties); err == nil && len(unused) > 0 {
+			logger.Warnf("Engine: Unused app properties %v", unused)
+		}
+
 		propProvider := app.GetPropertyProvider()
 		// Initialize the properties
-		for id, value := range e.App.Properties {
+		for id, value := range properties {
 			propProvider.SetProperty(id, value)
 		}
+		for _, def := range e.App.PropertyDefs {
+			propProvider.SetPropertyType(def.Name(), def.Type())
+		}
+
+		secretKey, err := app.LoadSecretKey()
+		if err != nil {
+			return err
+		}
+		propProvider.SetSecretKey(secretKey)
+
+		if secretsPath := config.GetSecretsPath(); secretsPath != "" {
+			propProvider.AddSecretResolver(app.NewFileSecretResolver(secretsPath))
+		}
 
 		data.SetPropertyProvider(propProvider)
+		data.SetSecretProvider(propProvider)
+		data.SetSchemaValidation(config.GetSchemaValidation())
 
 		actionFactories := action.Factories()
 		for _, factory := range actionFactories {
@@ -87,9 +119,15 @@ func (e *EngineConfig) Init(directRunner bool) error {
 			}
 		}
 
-		app.RegisterResources(e.App.Resources)
+		if err := app.RegisterResources(e.App.Resources); err != nil {
+			return fmt.Errorf("Engine: Error loading resources - %s", err.Error())
+		}
 
-		triggers, err := app.CreateTriggers(e.App.Triggers, e.actionRunner)
+		if deadLetterPath := config.GetDeadLetterPath(); deadLetterPath != "" {
+			trigger.SetDeadLetterStore(trigger.NewFileDeadLetterStore(deadLetterPath))
+		}
+
+		triggers, handlers, err := app.CreateTriggersWithHandlers(e.App.Triggers, e.actionRunner)
 
 		if err != nil {
 			errorMsg := fmt.Sprintf("Engine: Error Creating trigger instances - %s", err.Error())
@@ -98,6 +136,8 @@ func (e *EngineConfig) Init(directRunner bool) error {
 		}
 
 		e.triggers = triggers
+		e.handlers = handlers
+		e.initialized = true
 	}
 
 	return nil
@@ -109,7 +149,9 @@ func (e *EngineConfig) Start() error {
 
 	// Todo document RunnerType for engine configuration
 	runnerType := config.GetRunnerType()
-	e.Init(runnerType == "DIRECT")
+	if err := e.Init(runnerType == "DIRECT"); err != nil {
+		return err
+	}
 
 	actionRunner := e.actionRunner.(interface{})
 
@@ -117,6 +159,13 @@ func (e *EngineConfig) Start() error {
 		util.StartManaged("ActionRunner Service", managedRunner)
 	}
 
+	if err := startResourceManagers(resource.Managers()); err != nil {
+		if managedRunner, ok := actionRunner.(util.Managed); ok {
+			util.StopManaged("ActionRunner", managedRunner)
+		}
+		return err
+	}
+
 	logger.Info("Engine: Starting Services...")
 
 	err := e.serviceManager.Start()
@@ -163,6 +212,38 @@ func (e *EngineConfig) Start() error {
 	return nil
 }
 
+// startResourceManagers starts the managed resource managers in the order of their resource type, if
+// one of them fails to start the managers that were already started are stopped in reverse order
+func startResourceManagers(managers map[string]resource.Manager) error {
+
+	var resTypes []string
+	for resType := range managers {
+		resTypes = append(resTypes, resType)
+	}
+	sort.Strings(resTypes)
+
+	var started []string
+
+	for _, resType := range resTypes {
+		managedManager, ok := managers[resType].(util.Managed)
+		if !ok {
+			continue
+		}
+
+		err := util.StartManaged(fmt.Sprintf("Resource Manager [ %s ]", resType), managedManager)
+		if err != nil {
+			for i := len(started) - 1; i >= 0; i-- {
+				util.StopManaged(fmt.Sprintf("Resource Manager [ %s ]", started[i]), managers[started[i]].(util.Managed))
+			}
+			return fmt.Errorf("Engine: Error starting resource manager [ %s ] - %s", resType, err.Error())
+		}
+
+		started = append(started, resType)
+	}
+
+	return nil
+}
+
 func (e *EngineConfig) Stop() error {
 	logger.Info("Engine: Stopping...")
 
@@ -177,6 +258,12 @@ func (e *EngineConfig) Stop() error {
 		util.StopManaged("ActionRunner", managedRunner)
 	}
 
+	for resType, manager := range resource.Managers() {
+		if managedManager, ok := manager.(util.Managed); ok {
+			util.StopManaged(fmt.Sprintf("Resource Manager [ %s ]", resType), managedManager)
+		}
+	}
+
 	//TODO temporarily add services
 	logger.Info("Engine: Stopping Services...")
 
@@ -191,3 +278,25 @@ func (e *EngineConfig) Stop() error {
 	logger.Info("Engine: Stopped")
 	return nil
 }
+
+// DeadLetters lists the trigger events that failed to be handled
+func (e *EngineConfig) DeadLetters() ([]*trigger.DeadLetter, error) {
+
+	store := trigger.GetDeadLetterStore()
+	if store == nil {
+		return nil, errors.New("dead letter store not configured")
+	}
+
+	return store.List()
+}
+
+// ReplayDeadLetters replays the trigger events that failed to be handled through their original handler
+func (e *EngineConfig) ReplayDeadLetters(ctx context.Context) (int, error) {
+
+	store := trigger.GetDeadLetterStore()
+	if store == nil {
+		return 0, errors.New("dead letter store not configured")
+	}
+
+	return trigger.ReplayDeadLetters(ctx, store, e.handlers)
+}
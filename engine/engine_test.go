@@ -1,9 +1,13 @@
 package engine
 
 import (
+	"encoding/json"
+	"errors"
+	"testing"
+
 	"github.com/TIBCOSoftware/flogo-lib/app"
+	"github.com/TIBCOSoftware/flogo-lib/app/resource"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 //TestNewEngineErrorNoApp
@@ -33,3 +37,81 @@ func TestNewEngineErrorNoAppVersion(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "no App version provided", err.Error())
 }
+
+type managedManager struct {
+	name     string
+	startErr error
+	events   *[]string
+}
+
+func (m *managedManager) LoadResource(config *resource.Config) error { return nil }
+func (m *managedManager) GetResource(id string) interface{}          { return nil }
+
+func (m *managedManager) Start() error {
+	if m.startErr != nil {
+		return m.startErr
+	}
+	*m.events = append(*m.events, "start "+m.name)
+	return nil
+}
+
+func (m *managedManager) Stop() error {
+	*m.events = append(*m.events, "stop "+m.name)
+	return nil
+}
+
+//TestStartResourceManagers
+func TestStartResourceManagers(t *testing.T) {
+
+	var events []string
+
+	managers := map[string]resource.Manager{
+		"a": &managedManager{name: "a", events: &events},
+		"b": &managedManager{name: "b", events: &events},
+	}
+
+	assert.Nil(t, startResourceManagers(managers))
+	assert.Equal(t, []string{"start a", "start b"}, events)
+}
+
+//TestStartResourceManagersRollback
+func TestStartResourceManagersRollback(t *testing.T) {
+
+	var events []string
+
+	managers := map[string]resource.Manager{
+		"a": &managedManager{name: "a", events: &events},
+		"b": &managedManager{name: "b", events: &events},
+		"c": &managedManager{name: "c", events: &events, startErr: errors.New("failed")},
+	}
+
+	err := startResourceManagers(managers)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Engine: Error starting resource manager [ c ] - failed", err.Error())
+	assert.Equal(t, []string{"start a", "start b", "stop b", "stop a"}, events)
+}
+
+//TestStartErrorRequiredProperty
+func TestStartErrorRequiredProperty(t *testing.T) {
+
+	cfg := &app.Config{}
+	err := json.Unmarshal([]byte(`{
+	  "name": "MyApp",
+	  "version": "1.0.0",
+	  "properties": [
+	    { "name": "url", "type": "string", "required": true }
+	  ]
+	}`), cfg)
+	assert.Nil(t, err)
+
+	e, err := New(cfg)
+	assert.Nil(t, err)
+
+	err = e.Start()
+	assert.NotNil(t, err)
+	assert.Equal(t, "Engine: Invalid app properties - required properties not set: [url]", err.Error())
+
+	// a failed init isn't treated as initialized
+	assert.False(t, e.(*EngineConfig).initialized)
+	assert.NotNil(t, e.Start())
+}
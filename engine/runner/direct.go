@@ -49,6 +49,14 @@ func (runner *DirectRunner) Execute(ctx context.Context, act action.Action, inpu
 		return nil, errors.New("Action not specified")
 	}
 
+	execute := action.ApplyMiddleware(ctx, runner.run)
+
+	return execute(ctx, act, inputs)
+}
+
+// run runs the specified action, waiting for it to complete if it is asynchronous
+func (runner *DirectRunner) run(ctx context.Context, act action.Action, inputs map[string]*data.Attribute) (results map[string]*data.Attribute, err error) {
+
 	md := action.GetMetadata(act)
 
 	if !md.Async {
@@ -152,3 +152,29 @@ func TestStopOk(t *testing.T) {
 	assert.False(t, runner.active)
 
 }
+
+// TestRunWithMiddleware test that middlewares are applied around the action
+func TestRunWithMiddleware(t *testing.T) {
+	config := &PooledConfig{NumWorkers: 1, WorkQueueSize: 1}
+	runner := NewPooled(config)
+	err := runner.Start()
+	assert.Nil(t, err)
+
+	var called bool
+	ctx := action.NewContextWithMiddleware(context.Background(), func(next action.ExecuteFunc) action.ExecuteFunc {
+		return func(ctx context.Context, act action.Action, inputs map[string]*data.Attribute) (map[string]*data.Attribute, error) {
+			called = true
+			results, err := next(ctx, act, inputs)
+			results["data"], _ = data.NewAttribute("data", data.TypeString, "redacted")
+			return results, err
+		}
+	})
+
+	a := new(MockResultAction)
+	a.On("Run", mock.Anything, mock.AnythingOfType("map[string]*data.Attribute"), mock.AnythingOfType("*runner.AsyncResultHandler")).Return(nil)
+	results, err := runner.Execute(ctx, a, nil)
+	assert.Nil(t, err)
+	assert.True(t, called)
+	assert.Equal(t, 200, results["code"].Value())
+	assert.Equal(t, "redacted", results["data"].Value())
+}
@@ -93,36 +93,24 @@ func (w ActionWorker) Start() {
 
 					handler := &AsyncResultHandler{result: make(chan *ActionResult), done: make(chan bool, 1)}
 
-					md := action.GetMetadata(actionData.action)
+					execute := action.ApplyMiddleware(actionData.context, w.runAction(handler))
+					results, err := execute(actionData.context, actionData.action, actionData.inputs)
 
-					if !md.Async {
-						syncAct := actionData.action.(action.SyncAction)
-						results, err := syncAct.Run(actionData.context, actionData.inputs)
-						logger.Debugf("Action-Worker-%d: Received result: %v", w.ID, results)
-						actionData.arc <- &ActionResult{results: results, err: err}
+					if err != nil {
+						logger.Debugf("Action-Worker-%d: Action Run error: %s", w.ID, err.Error())
 					} else {
-						asyncAct := actionData.action.(action.AsyncAction)
-
-						err := asyncAct.Run(actionData.context, actionData.inputs, handler)
-
-						if err != nil {
-							logger.Debugf("Action-Worker-%d: Action Run error: %s", w.ID, err.Error())
-							// error so just return
-							actionData.arc <- &ActionResult{err: err}
-						} else {
-							done := false
-							//wait for reply
-							for !done {
-								select {
-								case result := <-handler.result:
-									logger.Debugf("Action-Worker-%d: Received result: %#v", w.ID, result)
-									actionData.arc <- result
-								case <-handler.done:
-									if !handler.replied {
-										actionData.arc <- &ActionResult{}
-									}
-									done = true
-								}
+						logger.Debugf("Action-Worker-%d: Received result: %v", w.ID, results)
+					}
+					actionData.arc <- &ActionResult{results: results, err: err}
+
+					if handler.running {
+						//wait for async action to complete
+						for done := false; !done; {
+							select {
+							case result := <-handler.result:
+								logger.Debugf("Action-Worker-%d: Received additional result: %#v", w.ID, result)
+							case <-handler.done:
+								done = true
 							}
 						}
 					}
@@ -139,6 +127,36 @@ func (w ActionWorker) Start() {
 	}()
 }
 
+// runAction returns an ExecuteFunc that runs the action, asynchronous actions
+// return as soon as the first result is available or they are done
+func (w ActionWorker) runAction(handler *AsyncResultHandler) action.ExecuteFunc {
+	return func(ctx context.Context, act action.Action, inputs map[string]*data.Attribute) (map[string]*data.Attribute, error) {
+
+		md := action.GetMetadata(act)
+
+		if !md.Async {
+			syncAct := act.(action.SyncAction)
+			return syncAct.Run(ctx, inputs)
+		}
+
+		asyncAct := act.(action.AsyncAction)
+
+		err := asyncAct.Run(ctx, inputs, handler)
+		if err != nil {
+			// error so just return
+			return nil, err
+		}
+
+		select {
+		case result := <-handler.result:
+			handler.running = true
+			return result.results, result.err
+		case <-handler.done:
+			return nil, nil
+		}
+	}
+}
+
 // Stop tells the worker to stop listening for work requests.
 //
 // Note that the worker will only stop *after* it has finished its work.
@@ -153,6 +171,7 @@ type AsyncResultHandler struct {
 	done    chan (bool)
 	result  chan (*ActionResult)
 	replied bool
+	running bool
 }
 
 // HandleResult implements action.ResultHandler.HandleResult